@@ -0,0 +1,203 @@
+package alb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadAlb reads an instance in the .alb format of the SALBP data sets of
+// Otto, Otto and Scholl (2013):
+//
+//	<number of tasks>
+//	20
+//
+//	<cycle time>
+//	1000
+//
+//	<order strength>
+//	0,268
+//
+//	<task times>
+//	1 142
+//	...
+//
+//	<precedence relations>
+//	1,6
+//	...
+//
+//	<end>
+//
+// The cycle time is added to the instance's CycleTimes and the order
+// strength, which uses a decimal comma, to its Metadata as "order strength".
+// Blank lines are skipped and anything after <end> is ignored. Sections of
+// the generalized data sets, such as <linked tasks>, are not supported.
+// Errors are *ParseErrors giving the line of the file.
+func ReadAlb(r io.Reader) (*Instance, error) {
+	inst := NewInstance("")
+	byID := make(map[int]*Task)
+	n := -1
+
+	// Relations are resolved once all task times are read, so that the
+	// sections may come in any order.
+	type relation struct {
+		pred, task, line int
+	}
+	var relations []relation
+
+	scanner := bufio.NewScanner(r)
+	var lineno int
+	var section string
+scan:
+	for scanner.Scan() {
+		lineno++
+		fail := func(format string, args ...interface{}) error {
+			return &ParseError{Format: "alb", Line: lineno, Err: fmt.Errorf(format, args...)}
+		}
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "<") && strings.HasSuffix(text, ">") {
+			section = strings.ToLower(strings.TrimSpace(text[1 : len(text)-1]))
+			switch section {
+			case "number of tasks", "cycle time", "order strength", "task times", "precedence relations":
+			case "end":
+				break scan
+			default:
+				return nil, fail("unsupported section %s", text)
+			}
+			continue
+		}
+
+		switch section {
+		case "":
+			return nil, fail("expected a section, got %q", text)
+		case "number of tasks":
+			v, err := strconv.Atoi(text)
+			if err != nil || v < 0 || n >= 0 {
+				return nil, fail("number of tasks: invalid count %q", text)
+			}
+			n = v
+		case "cycle time":
+			time, err := strconv.ParseFloat(text, 64)
+			if err != nil || time <= 0 || len(inst.CycleTimes) > 0 {
+				return nil, fail("cycle time: invalid cycle time %q", text)
+			}
+			inst.addCycleTime(time, 0)
+		case "order strength":
+			v, err := strconv.ParseFloat(strings.Replace(text, ",", ".", 1), 64)
+			if err != nil || v < 0 || v > 1 {
+				return nil, fail("order strength: invalid order strength %q", text)
+			}
+			inst.Metadata["order strength"] = strconv.FormatFloat(v, 'g', -1, 64)
+		case "task times":
+			fields := strings.Fields(text)
+			if len(fields) != 2 {
+				return nil, fail("task time: expected 2 fields, got %d", len(fields))
+			}
+
+			id, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, fail("task id: invalid id %q", fields[0])
+			}
+
+			time, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil || time < 0 {
+				return nil, fail("task %d: invalid time %q", id, fields[1])
+			}
+
+			if _, ok := byID[id]; ok {
+				return nil, fail("task %d: duplicate task", id)
+			}
+
+			task := NewTask(id, time)
+			byID[id] = task
+			inst.Tasks = append(inst.Tasks, task)
+		case "precedence relations":
+			fields := strings.Split(text, ",")
+			if len(fields) != 2 {
+				return nil, fail("precedence relation: expected 2 fields, got %d", len(fields))
+			}
+
+			predID, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+			if err != nil {
+				return nil, fail("precedence relation: invalid predecessor %q", fields[0])
+			}
+
+			taskID, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+			if err != nil {
+				return nil, fail("precedence relation: invalid successor %q", fields[1])
+			}
+
+			relations = append(relations, relation{predID, taskID, lineno})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("alb: %s", err)
+	}
+
+	if lineno == 0 {
+		return nil, errors.New("alb: file is empty")
+	}
+
+	if n < 0 {
+		return nil, errors.New("alb: missing <number of tasks>")
+	}
+
+	if len(inst.Tasks) != n {
+		return nil, fmt.Errorf("alb: expected %d task times, got %d", n, len(inst.Tasks))
+	}
+
+	for _, rel := range relations {
+		pred, ok := byID[rel.pred]
+		if !ok {
+			return nil, &ParseError{Format: "alb", Line: rel.line,
+				Err: fmt.Errorf("precedence relation: unknown predecessor %d", rel.pred)}
+		}
+
+		task, ok := byID[rel.task]
+		if !ok {
+			return nil, &ParseError{Format: "alb", Line: rel.line,
+				Err: fmt.Errorf("precedence relation: unknown successor %d", rel.task)}
+		}
+
+		task.AddPred(pred)
+	}
+
+	return inst, nil
+}
+
+// WriteAlb writes the line's tasks and precedence relations in the .alb
+// format read by ReadAlb, with the given cycle time and the order strength
+// of the line's precedence graph.
+func WriteAlb(w io.Writer, line *Line, cycleTime float64) error {
+	tasks := line.Tasks()
+	g := newPrecedenceGraph(tasks)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<number of tasks>\n%d\n\n", len(tasks))
+	fmt.Fprintf(bw, "<cycle time>\n%s\n\n", strconv.FormatFloat(cycleTime, 'g', -1, 64))
+
+	strength := strconv.FormatFloat(g.OrderStrength(), 'f', 3, 64)
+	fmt.Fprintf(bw, "<order strength>\n%s\n\n", strings.Replace(strength, ".", ",", 1))
+
+	fmt.Fprintf(bw, "<task times>\n")
+	for _, task := range tasks {
+		fmt.Fprintf(bw, "%d %s\n", task.ID, strconv.FormatFloat(task.Time(), 'g', -1, 64))
+	}
+
+	fmt.Fprintf(bw, "\n<precedence relations>\n")
+	for _, arc := range g.Arcs() {
+		fmt.Fprintf(bw, "%d,%d\n", arc[0], arc[1])
+	}
+
+	fmt.Fprintf(bw, "\n<end>\n")
+	return bw.Flush()
+}
@@ -0,0 +1,131 @@
+package alb
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadAlb(t *testing.T) {
+	input := strings.Join([]string{
+		"<number of tasks>",
+		"4",
+		"",
+		"<cycle time>",
+		"10",
+		"",
+		"<order strength>",
+		"0,833",
+		"",
+		"<task times>",
+		"1 3",
+		"2 5",
+		"3 2",
+		"4 4",
+		"",
+		"<precedence relations>",
+		"1,2",
+		"1,3",
+		"2,4",
+		"3,4",
+		"",
+		"<end>",
+		"trailing text",
+	}, "\r\n")
+
+	inst, err := ReadAlb(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadAlb returned an error, %s", err)
+	}
+
+	if len(inst.Tasks) != 4 || inst.Tasks[1].Time() != 5 {
+		t.Fatalf("ReadAlb() = 4 tasks, task 2 time 5, got %d tasks", len(inst.Tasks))
+	}
+
+	if got := inst.Tasks[3].Preds(); len(got) != 2 || got[0].ID != 2 || got[1].ID != 3 {
+		t.Errorf("task 4 preds = [2 3], got %v", got)
+	}
+
+	if len(inst.CycleTimes) != 1 || inst.CycleTimes[0] != 10 {
+		t.Errorf("inst.CycleTimes = [10], got %v", inst.CycleTimes)
+	}
+
+	if inst.Metadata["order strength"] != "0.833" {
+		t.Errorf("inst.Metadata[order strength] = 0.833, got %q", inst.Metadata["order strength"])
+	}
+}
+
+func TestReadAlbErrors(t *testing.T) {
+	var tests = []struct {
+		input string
+		line  int
+	}{
+		{"4\n", 1},
+		{"<number of tasks>\ntwo\n", 2},
+		{"<number of tasks>\n1\n<linked tasks>\n", 3},
+		{"<number of tasks>\n1\n<task times>\n1\n", 4},
+		{"<number of tasks>\n1\n<order strength>\n1,5\n", 4},
+		{"<number of tasks>\n1\n<task times>\n1 1\n<precedence relations>\n\n1,2\n", 7},
+		{"<number of tasks>\n1\n<precedence relations>\n1;2\n", 4},
+	}
+
+	for _, test := range tests {
+		_, err := ReadAlb(strings.NewReader(test.input))
+		perr, ok := err.(*ParseError)
+		if !ok || perr.Line != test.line {
+			t.Errorf("ReadAlb(%q) = error at line %d, got %v", test.input, test.line, err)
+		}
+	}
+
+	for _, input := range []string{"", "<cycle time>\n10\n", "<number of tasks>\n2\n<task times>\n1 1\n"} {
+		if _, err := ReadAlb(strings.NewReader(input)); err == nil {
+			t.Errorf("ReadAlb(%q) = error, got nil", input)
+		}
+	}
+}
+
+func TestWriteAlb(t *testing.T) {
+	file, err := os.Open("specs/buxey.in2")
+	if err != nil {
+		t.Fatalf("opening buxey.in2 returned an error, %s", err)
+	}
+	defer file.Close()
+
+	inst, err := ReadIn2(file)
+	if err != nil {
+		t.Fatalf("ReadIn2 returned an error, %s", err)
+	}
+
+	line, err := inst.Line()
+	if err != nil {
+		t.Fatalf("inst.Line() returned an error, %s", err)
+	}
+
+	var buf bytes.Buffer
+	err = WriteAlb(&buf, line, 33)
+	if err != nil {
+		t.Fatalf("WriteAlb returned an error, %s", err)
+	}
+
+	if !strings.Contains(buf.String(), "<order strength>\n0,507\n") {
+		t.Errorf("WriteAlb(buxey) = order strength 0,507, got\n%s", buf.String())
+	}
+
+	again, err := ReadAlb(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadAlb(WriteAlb()) returned an error, %s", err)
+	}
+
+	if len(again.Tasks) != 29 || len(again.CycleTimes) != 1 || again.CycleTimes[0] != 33 {
+		t.Fatalf("ReadAlb(WriteAlb(buxey)) = 29 tasks cycle time 33, got %d %v", len(again.Tasks), again.CycleTimes)
+	}
+
+	for i, task := range inst.Tasks {
+		other := again.Tasks[i]
+		if other.ID != task.ID || other.Time() != task.Time() || len(other.Preds()) != len(task.Preds()) {
+			t.Errorf("task %d = time %.2f %d preds, got task %d time %.2f %d preds",
+				task.ID, task.Time(), len(task.Preds()), other.ID, other.Time(), len(other.Preds()))
+		}
+	}
+}
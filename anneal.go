@@ -0,0 +1,171 @@
+package alb
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// AnnealOptions configures BalanceByAnnealing.
+type AnnealOptions struct {
+	// Objective is the cost being minimized. The zero value minimizes
+	// MinimizeStations.
+	Objective Objective
+
+	// The temperature starts at InitialTemperature (default 1) and is
+	// multiplied by Cooling (default 0.999) after every iteration, until it
+	// falls below MinTemperature (default 0.001).
+	InitialTemperature float64
+	Cooling            float64
+	MinTemperature     float64
+
+	// Iterations and TimeLimit stop the search early, if non-zero.
+	Iterations int
+	TimeLimit  time.Duration
+
+	// Seed seeds the random moves, so runs with the same seed and line
+	// produce the same balance.
+	Seed int64
+
+	// Heuristic builds the starting balance with BalanceByStationId if the
+	// line has free tasks. The default is LongestTaskTime.
+	Heuristic Heuristic
+}
+
+// AnnealResult describes the outcome of BalanceByAnnealing.
+type AnnealResult struct {
+	Stations   int
+	Smoothness float64
+	Cost       float64
+
+	// Iterations is the number of moves tried, and Accepted the number that
+	// were kept.
+	Iterations int
+	Accepted   int
+
+	// Solution is the balance assigned to the line.
+	Solution *Solution
+
+	Elapsed time.Duration
+}
+
+// BalanceByAnnealing improves a balance of the line by simulated annealing.
+// Each iteration tries a random shift or swap between active stations (see
+// Improve); moves that lower the cost are always kept, and moves that raise
+// it by d are kept with probability exp(-d/T) at temperature T. The lowest
+// cost balance seen is assigned to the line when it finishes.
+func (l *Line) BalanceByAnnealing(opts AnnealOptions) (*AnnealResult, error) {
+	start := time.Now()
+	if opts.Objective == (Objective{}) {
+		opts.Objective = MinimizeStations
+	}
+	if opts.InitialTemperature <= 0 {
+		opts.InitialTemperature = 1
+	}
+	if opts.Cooling <= 0 || opts.Cooling >= 1 {
+		opts.Cooling = 0.999
+	}
+	if opts.MinTemperature <= 0 {
+		opts.MinTemperature = 0.001
+	}
+	if opts.Heuristic == nil {
+		opts.Heuristic = LongestTaskTime
+	}
+
+	if l.NFreeTasks() > 0 {
+		err := l.reset()
+		if err != nil {
+			return nil, err
+		}
+
+		err = l.BalanceByStationId(opts.Heuristic)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	cost := opts.Objective.Cost(l)
+	best, bestCost := l.Snapshot(), cost
+	result := &AnnealResult{}
+	for temp := opts.InitialTemperature; temp >= opts.MinTemperature; temp *= opts.Cooling {
+		if opts.Iterations > 0 && result.Iterations >= opts.Iterations {
+			break
+		}
+		if opts.TimeLimit > 0 && time.Since(start) >= opts.TimeLimit {
+			break
+		}
+		result.Iterations++
+
+		m, ok := l.randomMove(rng)
+		if !ok {
+			continue
+		}
+
+		ok, err := l.do(&m)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		next := opts.Objective.Cost(l)
+		if d := next - cost; d > 0 && rng.Float64() >= math.Exp(-d/temp) {
+			err = l.undo(&m)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		result.Accepted++
+		cost = next
+		if cost < bestCost-eps {
+			best, bestCost = l.Snapshot(), cost
+		}
+	}
+
+	err := l.Restore(best)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Stations = l.NActiveStations()
+	result.Smoothness = l.smoothness()
+	result.Cost = bestCost
+	result.Solution = l.solution("anneal")
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
+
+// randomMove picks a random shift or swap between the line's active
+// stations, moving a task from a station that has tasks. Swaps are only
+// picked when the other station has tasks too. It returns false if the line
+// has fewer than two active stations, or none with tasks.
+func (l *Line) randomMove(rng *rand.Rand) (Move, bool) {
+	stations := l.ActiveStations()
+	var loaded []*Station
+	for _, station := range stations {
+		if station.NTasks() > 0 {
+			loaded = append(loaded, station)
+		}
+	}
+	if len(stations) < 2 || len(loaded) == 0 {
+		return Move{}, false
+	}
+
+	from := loaded[rng.Intn(len(loaded))]
+	to := stations[rng.Intn(len(stations)-1)]
+	if to == from {
+		to = stations[len(stations)-1]
+	}
+
+	task := from.Tasks()[rng.Intn(from.NTasks())]
+	if rng.Intn(2) == 0 || to.NTasks() == 0 {
+		return Move{Kind: Shift, Task: task.ID, To: to.ID}, true
+	}
+
+	other := to.Tasks()[rng.Intn(to.NTasks())]
+	return Move{Kind: Swap, Task: task.ID, Other: other.ID, To: to.ID}, true
+}
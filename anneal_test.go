@@ -0,0 +1,88 @@
+package alb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnnealing(t *testing.T) {
+	line := newTestLine("TestAnnealing", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+	err := line.Restore(newSnapshot(map[int][]int{1: {1}, 2: {2, 3}, 3: {4, 5}, 4: {6}}))
+	if err != nil {
+		t.Fatalf("line.Restore returned an error, %s", err)
+	}
+
+	opts := AnnealOptions{
+		Objective: Objective{Stations: 1, Smoothness: 0.1},
+		Seed:      7,
+	}
+	result, err := line.BalanceByAnnealing(opts)
+	if err != nil {
+		t.Fatalf("BalanceByAnnealing returned an error, %s", err)
+	}
+
+	if result.Stations > 3 || result.Stations != line.NActiveStations() {
+		t.Errorf("BalanceByAnnealing() = at most 3 stations, got %d (line %d)", result.Stations, line.NActiveStations())
+	}
+
+	if got := line.NFreeTasks(); got != 0 {
+		t.Errorf("line.NFreeTasks() = 0, got %d", got)
+	}
+
+	for _, station := range line.ActiveStations() {
+		if station.Time() > 10 {
+			t.Errorf("station %d time %.1f exceeds cycle time 10", station.ID, station.Time())
+		}
+	}
+}
+
+func TestAnnealingSeed(t *testing.T) {
+	var assignments []*Snapshot
+	for i := 0; i < 2; i++ {
+		line := newTestLine("TestAnnealingSeed", []float64{5, 4, 3, 3, 3, 2, 6, 1}, [][2]int{{1, 3}, {3, 5}}, 10)
+		_, err := line.BalanceByAnnealing(AnnealOptions{Objective: MinimizeSmoothness, Iterations: 500, Seed: 3})
+		if err != nil {
+			t.Fatalf("BalanceByAnnealing returned an error, %s", err)
+		}
+		assignments = append(assignments, line.Snapshot())
+	}
+
+	if !reflect.DeepEqual(assignments[0], assignments[1]) {
+		t.Errorf("BalanceByAnnealing(Seed: 3) = %v, got %v", assignments[0], assignments[1])
+	}
+}
+
+func TestAnnealingEmptyStation(t *testing.T) {
+	line := newTestLine("TestAnnealingEmptyStation", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+	err := line.Restore(newSnapshot(map[int][]int{1: {1, 2}, 2: {3, 4, 5}, 3: {6}}))
+	if err != nil {
+		t.Fatalf("line.Restore returned an error, %s", err)
+	}
+	line.Station(4).Activate()
+
+	_, err = line.BalanceByAnnealing(AnnealOptions{Seed: 1, Iterations: 200})
+	if err != nil {
+		t.Fatalf("BalanceByAnnealing returned an error, %s", err)
+	}
+
+	if got := line.NFreeTasks(); got != 0 {
+		t.Errorf("line.NFreeTasks() = 0, got %d", got)
+	}
+}
+
+func TestAnnealingKeepsPrecedence(t *testing.T) {
+	line := newTestLine("TestAnnealingKeepsPrecedence", []float64{6, 9, 4}, [][2]int{{2, 3}}, 10)
+	err := line.Restore(newSnapshot(map[int][]int{1: {1}, 2: {2}, 3: {3}}))
+	if err != nil {
+		t.Fatalf("line.Restore returned an error, %s", err)
+	}
+
+	_, err = line.BalanceByAnnealing(AnnealOptions{Seed: 1})
+	if err != nil {
+		t.Fatalf("BalanceByAnnealing returned an error, %s", err)
+	}
+
+	if err := line.Validate(); err != nil {
+		t.Errorf("line.Validate() after BalanceByAnnealing = nil, got %s", err)
+	}
+}
@@ -0,0 +1,192 @@
+package alb
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// BeamOptions configures BalanceByBeamSearch. Zero values select the
+// defaults given for each field.
+type BeamOptions struct {
+	// Width is the number of partial balances kept after each station
+	// (default 5). Wider beams take longer but find better balances.
+	Width int
+
+	// Heuristic orders the tasks when enumerating a station's loads
+	// (default LongestTaskTime).
+	Heuristic Heuristic
+
+	// Priorities, if set, ranks partial balances by the total priority of
+	// their assigned tasks. Otherwise they are ranked by their idle time.
+	Priorities Priorities
+
+	// Loads is the number of loads of each station expanded per partial
+	// balance (default 100).
+	Loads int
+
+	// TimeLimit stops expanding after the given duration, if non-zero,
+	// completing the remaining stations with BalanceByStationId.
+	TimeLimit time.Duration
+}
+
+// BeamResult describes the outcome of BalanceByBeamSearch.
+type BeamResult struct {
+	Stations   int
+	FreeTasks  int
+	Smoothness float64
+
+	// Expanded is the number of partial balances generated.
+	Expanded int
+
+	// Solution is the balance assigned to the line.
+	Solution *Solution
+
+	Elapsed time.Duration
+}
+
+// BalanceByBeamSearch balances the line by beam search. Like
+// BalanceByStationId it loads the stations in order by id, but rather than
+// committing to one load per station it expands every kept partial balance
+// with each maximal load of the next station (see BalanceByBranchAndBound),
+// and keeps the Width best partial balances.
+//
+// Partial balances are ranked by idle time, i.e. the time left unused in
+// their loaded stations at the line's cycle time, or by Priorities. The best
+// complete balance (fewest free tasks, then fewest stations, then the lowest
+// smoothness index) is assigned to the line.
+//
+// Only Forward lines are supported.
+func (l *Line) BalanceByBeamSearch(opts BeamOptions) (*BeamResult, error) {
+	start := time.Now()
+	if l.Direction != Forward {
+		return nil, fmt.Errorf("%s balancing is not supported by beam search", l.Direction)
+	}
+
+	if opts.Width <= 0 {
+		opts.Width = 5
+	}
+	if opts.Heuristic == nil {
+		opts.Heuristic = LongestTaskTime
+	}
+	if opts.Loads <= 0 {
+		opts.Loads = 100
+	}
+
+	cycle, _ := l.CycleTime()
+	expired := func() bool {
+		return opts.TimeLimit > 0 && time.Since(start) >= opts.TimeLimit
+	}
+
+	result := &BeamResult{}
+	var best *Snapshot
+	var bestScore balanceScore
+	keep := func() {
+		score := l.score()
+		if best == nil || score.less(bestScore) {
+			best = l.Snapshot()
+			bestScore = score
+		}
+	}
+
+	beam := []*partialBalance{{assignment: newSnapshot(nil)}}
+	for _, station := range l.Stations() {
+		if len(beam) == 0 {
+			break
+		}
+
+		var next []*partialBalance
+		for _, partial := range beam {
+			err := l.Restore(partial.assignment)
+			if err != nil {
+				return nil, err
+			}
+
+			if expired() {
+				err = l.BalanceByStationId(opts.Heuristic)
+				if err != nil {
+					return nil, err
+				}
+				keep()
+				continue
+			}
+
+			var loads int
+			stop := func() bool { return loads >= opts.Loads }
+			err = l.maximalLoads(station, opts.Heuristic, stop, func() error {
+				loads++
+				result.Expanded++
+
+				child := &partialBalance{rank: partial.rank}
+				if station.NTasks() > 0 {
+					station.Activate()
+					child.rank += l.rankLoad(station, cycle, opts.Priorities)
+				}
+				child.assignment = l.Snapshot()
+
+				if l.NFreeTasks() == 0 {
+					keep()
+				} else {
+					next = append(next, child)
+				}
+
+				station.Disable()
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		sort.SliceStable(next, func(i, j int) bool { return next[i].rank > next[j].rank })
+		if len(next) > opts.Width {
+			next = next[:opts.Width]
+		}
+		beam = next
+	}
+
+	// Partial balances that ran out of stations still count, in case no
+	// balance assigned every task.
+	for _, partial := range beam {
+		err := l.Restore(partial.assignment)
+		if err != nil {
+			return nil, err
+		}
+		keep()
+	}
+
+	err := l.Restore(best)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Stations = bestScore.stations
+	result.FreeTasks = bestScore.free
+	result.Smoothness = bestScore.smoothness
+	result.Solution = l.solution("beam")
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
+
+// partialBalance is an assignment of the first stations of a line.
+type partialBalance struct {
+	assignment *Snapshot
+
+	// rank is higher for better partial balances.
+	rank float64
+}
+
+// rankLoad returns how much a station's load adds to the rank of a partial
+// balance: minus its idle time, or the total priority of its tasks if
+// priorities are given.
+func (l *Line) rankLoad(station *Station, cycle float64, priorities Priorities) float64 {
+	if priorities == nil {
+		return station.Time() - cycle
+	}
+
+	var total float64
+	for _, task := range station.Tasks() {
+		total += priorities[task.ID]
+	}
+	return total
+}
@@ -0,0 +1,47 @@
+package alb
+
+import "testing"
+
+func TestBeamSearch(t *testing.T) {
+	var tests = []struct {
+		width int
+		want  int
+	}{
+		// Even a beam of one beats the 3 stations of LongestTaskTime, since
+		// it commits to the load with the least idle time.
+		{1, 2},
+		{5, 2},
+	}
+
+	for _, test := range tests {
+		line := newTestLine("TestBeamSearch", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+
+		result, err := line.BalanceByBeamSearch(BeamOptions{Width: test.width})
+		if err != nil {
+			t.Fatalf("BalanceByBeamSearch returned an error, %s", err)
+		}
+
+		if result.Stations != test.want || result.FreeTasks != 0 {
+			t.Errorf("BalanceByBeamSearch(Width: %d) = %d stations 0 free, got %d stations %d free",
+				test.width, test.want, result.Stations, result.FreeTasks)
+		}
+
+		if got := line.NActiveStations(); got != result.Stations {
+			t.Errorf("line.NActiveStations() = %d, got %d", result.Stations, got)
+		}
+	}
+}
+
+func TestBeamSearchPriorities(t *testing.T) {
+	preds := [][2]int{{1, 2}, {2, 3}}
+	line := newTestLine("TestBeamSearchPriorities", []float64{6, 6, 6, 2}, preds, 10)
+
+	result, err := line.BalanceByBeamSearch(BeamOptions{Priorities: RankedPositionalWeight(line)})
+	if err != nil {
+		t.Fatalf("BalanceByBeamSearch returned an error, %s", err)
+	}
+
+	if result.Stations != 3 || line.NFreeTasks() != 0 {
+		t.Errorf("BalanceByBeamSearch() = 3 stations 0 free, got %d stations %d free", result.Stations, line.NFreeTasks())
+	}
+}
@@ -0,0 +1,192 @@
+package alb
+
+import "math"
+
+// eps absorbs float error when comparing task times against fractions of
+// the cycle time.
+const eps = 1e-9
+
+// LowerBounds holds lower bounds on the number of stations needed to balance
+// a line at a given cycle time (SALBP-1). Any balance of the line uses at
+// least Best() stations.
+type LowerBounds struct {
+	// Capacity (LB1) is the total task time divided by the cycle time.
+	Capacity int
+
+	// HalfCycle (LB2) counts tasks longer than half the cycle time, since no
+	// two of them can share a station.
+	HalfCycle int
+
+	// ThirdCycle (LB3) weights tasks by how many of them fit in a station,
+	// using thirds of the cycle time.
+	ThirdCycle int
+
+	// Precedence is the best bound from the earliest and latest stations
+	// each task can be assigned to given its predecessors and successors.
+	Precedence int
+
+	// BinPacking is the Martello-Toth L2 bound for packing the task times
+	// into bins the size of the cycle time.
+	BinPacking int
+}
+
+// ComputeLowerBounds calculates lower bounds on the number of stations for
+// the line's tasks at the given cycle time.
+func ComputeLowerBounds(line *Line, time float64) *LowerBounds {
+	if time <= 0 {
+		return &LowerBounds{}
+	}
+
+	tasks := line.Tasks()
+	return &LowerBounds{
+		Capacity:   capacityBound(tasks, time),
+		HalfCycle:  halfCycleBound(tasks, time),
+		ThirdCycle: thirdCycleBound(tasks, time),
+		Precedence: precedenceBound(tasks, time),
+		BinPacking: binPackingBound(tasks, time),
+	}
+}
+
+// Best returns the largest of the lower bounds.
+func (b *LowerBounds) Best() int {
+	best := b.Capacity
+	for _, n := range []int{b.HalfCycle, b.ThirdCycle, b.Precedence, b.BinPacking} {
+		if n > best {
+			best = n
+		}
+	}
+	return best
+}
+
+// NoGap is the Gap of a line with free tasks, whose balance is incomplete.
+const NoGap = -1
+
+// Gap returns how many more stations the line uses than the best lower
+// bound. A gap of 0 means the line's balance is optimal. It returns NoGap
+// if any task is left free, since the number of stations in use then says
+// nothing about the bounds.
+func (b *LowerBounds) Gap(line *Line) int {
+	if line.NFreeTasks() > 0 {
+		return NoGap
+	}
+	return line.NActiveStations() - b.Best()
+}
+
+// stations returns the number of stations needed for the given work.
+func stations(work, time float64) int {
+	if work <= 0 {
+		return 0
+	}
+	return int(math.Ceil(work/time - eps))
+}
+
+func capacityBound(tasks []*Task, time float64) int {
+	var total float64
+	for _, task := range tasks {
+		total += task.Time()
+	}
+	return stations(total, time)
+}
+
+func halfCycleBound(tasks []*Task, time float64) int {
+	var long, half int
+	for _, task := range tasks {
+		switch t := task.Time(); {
+		case t > time/2+eps:
+			long++
+		case t > time/2-eps:
+			half++
+		}
+	}
+	return long + (half+1)/2
+}
+
+func thirdCycleBound(tasks []*Task, time float64) int {
+	var total float64
+	for _, task := range tasks {
+		switch t := task.Time(); {
+		case t > 2*time/3+eps:
+			total++
+		case t > 2*time/3-eps:
+			total += 2.0 / 3
+		case t > time/3+eps:
+			total += 0.5
+		case t > time/3-eps:
+			total += 1.0 / 3
+		}
+	}
+	return int(math.Ceil(total - eps))
+}
+
+// precedenceBound uses the fact that a task cannot be assigned before the
+// station that its predecessors fill, nor after the station from which its
+// successors would overflow the line. For each task, the work of the task
+// and all of its predecessors needs at least E stations, and the work of the
+// task and all of its successors needs at least T stations, sharing the one
+// the task is assigned to.
+func precedenceBound(tasks []*Task, time float64) int {
+	heads := make(map[int]float64)
+	tails := make(map[int]float64)
+	g := newPrecedenceGraph(tasks)
+	for _, task := range tasks {
+		heads[task.ID] = task.Time()
+		for _, pred := range g.AllPreds(task.ID) {
+			heads[task.ID] += pred.Time()
+		}
+
+		tails[task.ID] = task.Time()
+		for _, succ := range g.AllSuccs(task.ID) {
+			tails[task.ID] += succ.Time()
+		}
+	}
+
+	var best int
+	for _, task := range tasks {
+		n := stations(heads[task.ID], time) + stations(tails[task.ID], time) - 1
+		if n > best {
+			best = n
+		}
+	}
+	return best
+}
+
+// binPackingBound is the Martello-Toth L2 bound. For each threshold a up to
+// half the cycle time, tasks longer than the cycle time less a each need
+// their own station, tasks longer than half the cycle time need a station
+// each, and the time left over in those stations can absorb tasks between a
+// and half the cycle time before more stations are needed.
+func binPackingBound(tasks []*Task, time float64) int {
+	thresholds := []float64{0}
+	for _, task := range tasks {
+		if task.Time() <= time/2+eps {
+			thresholds = append(thresholds, task.Time())
+		}
+	}
+
+	var best int
+	for _, a := range thresholds {
+		var n1, n2 int
+		var sum2, sum3 float64
+		for _, task := range tasks {
+			switch t := task.Time(); {
+			case t > time-a+eps:
+				n1++
+			case t > time/2+eps:
+				n2++
+				sum2 += t
+			case t >= a-eps:
+				sum3 += t
+			}
+		}
+
+		n := n1 + n2
+		if extra := sum3 - (float64(n2)*time - sum2); extra > 0 {
+			n += stations(extra, time)
+		}
+
+		if n > best {
+			best = n
+		}
+	}
+	return best
+}
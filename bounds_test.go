@@ -0,0 +1,55 @@
+package alb
+
+import "testing"
+
+func TestLowerBounds(t *testing.T) {
+	var tests = []struct {
+		times []float64
+		preds [][2]int
+		time  float64
+		want  LowerBounds
+	}{
+		// Three tasks over half the cycle time need three stations even
+		// though their total time fits in two.
+		{[]float64{6, 6, 6}, nil, 10, LowerBounds{2, 3, 2, 1, 3}},
+		// Two tasks of exactly half the cycle time can share a station.
+		{[]float64{5, 5, 5}, nil, 10, LowerBounds{2, 2, 2, 1, 2}},
+		// Tasks a little over a third of the cycle time count as half a
+		// station each.
+		{[]float64{4, 4, 4, 4}, nil, 10, LowerBounds{2, 0, 2, 1, 2}},
+		// The middle task of the chain can share a station with neither
+		// neighbour.
+		{[]float64{4, 7, 4}, [][2]int{{1, 2}, {2, 3}}, 10, LowerBounds{2, 1, 2, 3, 2}},
+	}
+
+	for _, test := range tests {
+		line := newTestLine("TestLowerBounds", test.times, test.preds, test.time)
+		got := ComputeLowerBounds(line, test.time)
+		if *got != test.want {
+			t.Errorf("ComputeLowerBounds(%v, %.1f) = %+v, got %+v", test.times, test.time, test.want, *got)
+		}
+	}
+}
+
+func TestLowerBoundsGap(t *testing.T) {
+	line := newTestLine("TestLowerBoundsGap", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+	_ = line.BalanceByStationId(LongestTaskTime)
+
+	bounds := ComputeLowerBounds(line, 10)
+	if got := bounds.Best(); got != 2 {
+		t.Errorf("bounds.Best() = 2, got %d", got)
+	}
+
+	if got := bounds.Gap(line); got != 1 {
+		t.Errorf("bounds.Gap() = 1, got %d", got)
+	}
+
+	err := line.Task(6).Assignment().WithdrawTask(6)
+	if err != nil {
+		t.Fatalf("WithdrawTask returned an error, %s", err)
+	}
+
+	if got := bounds.Gap(line); got != NoGap {
+		t.Errorf("bounds.Gap() with a free task = NoGap, got %d", got)
+	}
+}
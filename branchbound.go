@@ -0,0 +1,255 @@
+package alb
+
+import (
+	"fmt"
+	"time"
+)
+
+// BranchAndBoundLimits bounds the work done by BalanceByBranchAndBound. A
+// zero value for a field means that resource is unlimited.
+type BranchAndBoundLimits struct {
+	Nodes int
+	Time  time.Duration
+}
+
+// BranchAndBoundResult describes the outcome of BalanceByBranchAndBound.
+type BranchAndBoundResult struct {
+	// Stations is the number of stations used by the best balance found, or
+	// 0 if no balance assigning every task was found.
+	Stations int
+
+	// Optimal is true if the search finished within its limits, proving
+	// that Stations is the minimum (or, when Stations is 0, that no balance
+	// assigning every task exists).
+	Optimal bool
+
+	Nodes   int
+	Elapsed time.Duration
+
+	// Solution is the balance assigned to the line.
+	Solution *Solution
+}
+
+// BalanceByBranchAndBound finds a balance of the line using the fewest
+// stations. It is a station-oriented branch and bound in the style of
+// SALOME: stations are loaded in order by id, and every maximal load that
+// satisfies the line's constraints is branched on. The balance produced by
+// BalanceByStationId with the given heuristic is the initial upper bound,
+// and the heuristic also orders the branches so good loads are tried first.
+//
+// Loads are pruned using the lower bounds of ComputeLowerBounds at the
+// line's cycle time (see CycleTime) when it has one. The best balance found
+// is assigned to the line when the search finishes or runs out of its
+// limits.
+//
+// Only Forward lines are supported.
+func (l *Line) BalanceByBranchAndBound(fn Heuristic, limits BranchAndBoundLimits) (*BranchAndBoundResult, error) {
+	if l.Direction != Forward {
+		return nil, fmt.Errorf("%s balancing is not supported by branch and bound", l.Direction)
+	}
+
+	b := &branchAndBound{
+		line:     l,
+		fn:       fn,
+		limits:   limits,
+		stations: l.Stations(),
+		start:    time.Now(),
+	}
+	b.cycle, _ = l.CycleTime()
+
+	err := l.reset()
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.BalanceByStationId(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	// A heuristic balance that leaves tasks free is not a valid upper bound,
+	// but it is still the best thing to leave on the line if the search
+	// finds nothing better.
+	fallback := l.Snapshot()
+	b.upper = len(b.stations) + 1
+	if l.NFreeTasks() == 0 {
+		b.best = fallback
+		b.upper = l.NActiveStations()
+	}
+
+	b.lower = b.bound(l.Tasks())
+	if b.cycle > 0 {
+		b.lower = ComputeLowerBounds(l, b.cycle).Best()
+	}
+	if b.upper > b.lower {
+		err = l.reset()
+		if err != nil {
+			return nil, err
+		}
+
+		err = b.search(0, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result := &BranchAndBoundResult{
+		Optimal: !b.aborted || b.upper == b.lower,
+		Nodes:   b.nodes,
+		Elapsed: time.Since(b.start),
+	}
+
+	best := fallback
+	if b.best != nil {
+		result.Stations = b.upper
+		best = b.best
+	}
+
+	err = l.Restore(best)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Solution = l.solution("branch-and-bound")
+	return result, nil
+}
+
+type branchAndBound struct {
+	line     *Line
+	fn       Heuristic
+	limits   BranchAndBoundLimits
+	stations []*Station
+	cycle    float64
+
+	lower int
+	upper int
+	best  *Snapshot
+
+	nodes   int
+	start   time.Time
+	aborted bool
+}
+
+// done reports whether the search should unwind, either because a balance
+// meeting the lower bound was found or because a limit was reached.
+func (b *branchAndBound) done() bool {
+	if b.upper <= b.lower || b.aborted {
+		return true
+	}
+
+	if b.limits.Nodes > 0 && b.nodes >= b.limits.Nodes {
+		b.aborted = true
+	}
+	if b.limits.Time > 0 && time.Since(b.start) >= b.limits.Time {
+		b.aborted = true
+	}
+	return b.aborted
+}
+
+// bound returns a lower bound on the number of stations needed for the
+// given tasks.
+func (b *branchAndBound) bound(tasks []*Task) int {
+	if len(tasks) == 0 {
+		return 0
+	}
+
+	if b.cycle <= 0 {
+		return 1
+	}
+
+	n := capacityBound(tasks, b.cycle)
+	if h := halfCycleBound(tasks, b.cycle); h > n {
+		n = h
+	}
+	if t := thirdCycleBound(tasks, b.cycle); t > n {
+		n = t
+	}
+	return n
+}
+
+// search loads stations[k:] given that used stations have been loaded so far.
+func (b *branchAndBound) search(k, used int) error {
+	if b.line.NFreeTasks() == 0 {
+		if used < b.upper {
+			b.upper = used
+			b.best = b.line.Snapshot()
+		}
+		return nil
+	}
+
+	if k == len(b.stations) || b.done() {
+		return nil
+	}
+
+	if used+b.bound(b.line.FreeTasks()) >= b.upper {
+		return nil
+	}
+
+	b.nodes++
+	station := b.stations[k]
+	return b.line.maximalLoads(station, b.fn, b.done, func() error {
+		if station.NTasks() == 0 {
+			return b.search(k+1, used)
+		}
+
+		station.Activate()
+		err := b.search(k+1, used+1)
+		station.Disable()
+		return err
+	})
+}
+
+// maximalLoads enumerates every maximal load of the station: the sets of
+// tasks that can be assigned to it together under the line's constraints
+// and that no other valid task could be added to. emit is called with each
+// load assigned to the station, and the load is withdrawn after it returns.
+// Tasks are tried in the order picked by the heuristic. The enumeration ends
+// early once stop returns true.
+func (l *Line) maximalLoads(station *Station, fn Heuristic, stop func() bool, emit func() error) error {
+	return l.loads(station, fn, make(map[int]bool), stop, emit)
+}
+
+// loads enumerates the maximal loads that do not contain an excluded task.
+// Each load is generated once: a task is either included, or excluded from
+// all deeper loads. Loads that an excluded task could still be added to are
+// dominated and skipped.
+func (l *Line) loads(station *Station, fn Heuristic, excluded map[int]bool, stop func() bool, emit func() error) error {
+	if stop() {
+		return nil
+	}
+
+	valid := l.ValidAssignments(station.ID)
+	if len(valid) == 0 {
+		return emit()
+	}
+
+	var candidates []*Task
+	for _, task := range valid {
+		if !excluded[task.ID] {
+			candidates = append(candidates, task)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	task := fn(candidates)
+	err := station.AssignTask(task)
+	if err != nil {
+		return err
+	}
+
+	err = l.loads(station, fn, excluded, stop, emit)
+	if werr := station.WithdrawTask(task.ID); err == nil {
+		err = werr
+	}
+	if err != nil {
+		return err
+	}
+
+	excluded[task.ID] = true
+	err = l.loads(station, fn, excluded, stop, emit)
+	delete(excluded, task.ID)
+	return err
+}
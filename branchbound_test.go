@@ -0,0 +1,87 @@
+package alb
+
+import "testing"
+
+// newTestLine returns a line with one station per task, balanced under the
+// given cycle time with single assignment and predecessor constraints.
+func newTestLine(name string, times []float64, preds [][2]int, cycle float64) *Line {
+	line := NewLine(name)
+	for i, time := range times {
+		_ = line.AddTask(NewTask(i+1, time))
+		_ = line.AddStation(NewStation(i + 1))
+	}
+
+	for _, pred := range preds {
+		line.Task(pred[1]).AddPred(line.Task(pred[0]))
+	}
+
+	line.AddConstraints([]Constraint{
+		&SingleTaskAssignment{},
+		&RestrictedStationTime{Time: cycle},
+		&PredecessorsStartToStart{},
+	})
+	return line
+}
+
+func TestBranchAndBoundImprovesHeuristic(t *testing.T) {
+	// LongestTaskTime loads 5+4, 3+3+3 and 2, but 5+3+2 and 4+3+3 fit in
+	// two stations.
+	line := newTestLine("TestBranchAndBoundImprovesHeuristic", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+
+	result, err := line.BalanceByBranchAndBound(LongestTaskTime, BranchAndBoundLimits{})
+	if err != nil {
+		t.Fatalf("BalanceByBranchAndBound returned an error, %s", err)
+	}
+
+	if result.Stations != 2 || !result.Optimal {
+		t.Errorf("BalanceByBranchAndBound() = 2 optimal, got %d %t", result.Stations, result.Optimal)
+	}
+
+	if got := line.NActiveStations(); got != 2 {
+		t.Errorf("line.NActiveStations() = 2, got %d", got)
+	}
+
+	if got := line.NFreeTasks(); got != 0 {
+		t.Errorf("line.NFreeTasks() = 0, got %d", got)
+	}
+}
+
+func TestBranchAndBoundPrecedence(t *testing.T) {
+	// The chain 1 -> 2 -> 3 forces task 3 after task 2, so three stations
+	// are needed even though the total work fits in two.
+	line := newTestLine("TestBranchAndBoundPrecedence", []float64{6, 6, 6}, [][2]int{{1, 2}, {2, 3}}, 10)
+
+	result, err := line.BalanceByBranchAndBound(LongestTaskTime, BranchAndBoundLimits{})
+	if err != nil {
+		t.Fatalf("BalanceByBranchAndBound returned an error, %s", err)
+	}
+
+	if result.Stations != 3 || !result.Optimal {
+		t.Errorf("BalanceByBranchAndBound() = 3 optimal, got %d %t", result.Stations, result.Optimal)
+	}
+
+	for _, pred := range [][2]int{{1, 2}, {2, 3}} {
+		from := line.Task(pred[0]).Assignment().ID
+		to := line.Task(pred[1]).Assignment().ID
+		if from > to {
+			t.Errorf("task %d at station %d after task %d at station %d", pred[0], from, pred[1], to)
+		}
+	}
+}
+
+func TestBranchAndBoundNodeLimit(t *testing.T) {
+	line := newTestLine("TestBranchAndBoundNodeLimit", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+
+	result, err := line.BalanceByBranchAndBound(LongestTaskTime, BranchAndBoundLimits{Nodes: 1})
+	if err != nil {
+		t.Fatalf("BalanceByBranchAndBound returned an error, %s", err)
+	}
+
+	if result.Optimal {
+		t.Error("BalanceByBranchAndBound(Nodes: 1).Optimal = false, got true")
+	}
+
+	if result.Stations == 0 || line.NFreeTasks() != 0 {
+		t.Errorf("BalanceByBranchAndBound(Nodes: 1) kept no balance, got %d stations", result.Stations)
+	}
+}
@@ -1,27 +1,29 @@
 package main
 
 import (
-	"bufio"
-	"errors"
 	"fmt"
 	"io"
 	"os"
-	"strconv"
+	"path/filepath"
 	"strings"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/parallelworks/alb"
 )
 
-var (
-	heuristicMap = map[string]alb.Heuristic{
-		"ShortestTaskTime": alb.ShortestTaskTime,
-		"LongestTaskTime":  alb.LongestTaskTime,
+// stoh looks up a heuristic by name. Priority rules are computed from the
+// line, so it should be called once the line's tasks and constraints are set.
+func stoh(line *alb.Line, heuristic string) alb.Heuristic {
+	if h, ok := alb.Heuristics[heuristic]; ok {
+		return h
+	}
+
+	if rule, ok := alb.PriorityRules[heuristic]; ok {
+		return rule(line).Heuristic()
 	}
-)
 
-func stoh(heuristic string) alb.Heuristic {
-	return heuristicMap[heuristic]
+	log.Fatalf("balance: unknown heuristic %q", heuristic)
+	return nil
 }
 
 func GetStream(filename string) (io.Reader, error) {
@@ -33,120 +35,53 @@ func GetStream(filename string) (io.Reader, error) {
 	return file, nil
 }
 
-func getLines(in io.Reader) ([]string, error) {
-	var lines []string
+// readerMap holds the instance readers by file format.
+var readerMap = map[string]func(io.Reader) (*alb.Instance, error){
+	"in2": alb.ReadIn2,
+	"csv": alb.ReadCSV,
+	"alb": alb.ReadAlb,
+}
 
-	spec := bufio.NewScanner(in)
-	for spec.Scan() {
-		lines = append(lines, strings.Trim(spec.Text(), "\n\r"))
-	}
+// definitionFormats are the extensions of line definitions, which are not
+// instances the balance command can read.
+var definitionFormats = map[string]bool{"json": true, "yaml": true, "yml": true}
 
-	if err := spec.Err(); err != nil {
-		return nil, fmt.Errorf("lines: file: %s", err)
+// fileFormat returns the given format, or the file's extension if it is
+// empty. Files without an extension are read as in2.
+func fileFormat(filename, format string) string {
+	if format != "" {
+		return strings.ToLower(format)
 	}
 
-	if len(lines) == 0 {
-		return nil, errors.New("lines: file is empty")
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+	if ext == "" {
+		return "in2"
 	}
-
-	return lines, nil
+	return ext
 }
 
-func taskById(id int, tasks []*alb.Task) *alb.Task {
-	for _, task := range tasks {
-		if task.ID == id {
-			return task
-		}
+// ParseFile reads an instance in the given format, returning it and one
+// station per task.
+func ParseFile(in io.Reader, format string) (*alb.Instance, []*alb.Station, error) {
+	read, ok := readerMap[format]
+	switch {
+	case !ok && definitionFormats[format]:
+		return nil, nil, fmt.Errorf("%s line definitions are not supported, -format must be in2, csv or alb", format)
+	case !ok:
+		return nil, nil, fmt.Errorf("unknown file format %q, -format must be in2, csv or alb", format)
 	}
-	return nil
-}
 
-func ParseIn2File(in io.Reader) ([]*alb.Task, []*alb.Station, error) {
-	lines, err := getLines(in)
+	inst, err := read(in)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	nTasks, err := strconv.ParseInt(lines[0], 10, 32)
-	if err != nil {
-		return nil, nil, fmt.Errorf("parse: number of tasks: %s", err)
-	}
-
-	times := lines[1 : nTasks+1]
-	predIds := lines[nTasks+1:]
-
-	tasks := make([]*alb.Task, nTasks)
-	stations := make([]*alb.Station, nTasks)
-	for i, t := range times {
-		parts := strings.Split(t, ",")
-		if err != nil {
-			return nil, nil, fmt.Errorf("parse: times: %s", err)
-		}
-		if len(parts) == 2 {
-			taskId, err := strconv.Atoi(parts[0])
-			if err != nil {
-				return nil, nil, fmt.Errorf("parse: taskId: %s", err)
-			}
-			taskTime, err := strconv.ParseFloat(parts[1], 64)
-			if err != nil {
-				return nil, nil, fmt.Errorf("parse: taskTime: %s", err)
-			}
-			task := alb.NewTask(taskId, taskTime)
-			tasks[i] = task
-		} else if len(parts) == 1 {
-			taskTime, err := strconv.ParseFloat(t, 64)
-			if err != nil {
-				return nil, nil, fmt.Errorf("parse: task time: line %d: %s", i+1, err)
-			}
-			task := alb.NewTask(i+1, taskTime)
-			tasks[i] = task
-		} else {
-			return nil, nil, fmt.Errorf("parse: preds: %s", err)
-		}
-
-		station := alb.NewStation(i + 1)
-		stations[i] = station
-
-	}
-
-	for _, ids := range predIds {
-		parts := strings.Split(ids, ",")
-		if err != nil {
-			return nil, nil, fmt.Errorf("parse: preds: %s", err)
-		}
-
-		if len(parts) != 2 {
-			return nil, nil, fmt.Errorf("parse: preds: %s", err)
-		}
-
-		predId, err := strconv.Atoi(parts[0])
-		if err != nil {
-			return nil, nil, fmt.Errorf("parse: pred: %s", err)
-		}
-
-		taskId, err := strconv.Atoi(parts[1])
-		if err != nil {
-			return nil, nil, fmt.Errorf("parse: pred: %s", err)
-		}
-
-		if predId == -1 && taskId == -1 {
-			continue
-		}
-
-		pred := taskById(predId, tasks)
-		if pred == nil {
-			return nil, nil, errors.New("parse: pred: attempting to assign an unknown task")
-		}
-
-		task := taskById(taskId, tasks)
-		if task == nil {
-			return nil, nil, errors.New("parse pred: attempting to assign pred to unknown task")
-		}
-
-		task.AddPred(pred)
+	stations := make([]*alb.Station, len(inst.Tasks))
+	for i := range inst.Tasks {
+		stations[i] = alb.NewStation(i + 1)
 	}
 
-	return tasks, stations, nil
+	return inst, stations, nil
 }
 
 // ValidateLine is a temporary hack to validate 2 conditions:
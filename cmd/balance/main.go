@@ -2,22 +2,48 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"strings"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/parallelworks/alb"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		validate(os.Args[2:])
+		return
+	}
+
 	var (
-		filename  = flag.String("file", "", "input in2 file")
-		cycleTime = flag.Float64("cycle", 60.0, "cycle time of line")
+		filename  = flag.String("file", "", "input file")
+		format    = flag.String("format", "", "input file format: in2, csv or alb (default the file's extension, or in2 without one)")
+		cycleTime = flag.Float64("cycle", 60.0, "cycle time of line, if the file does not give one")
 		heuristic = flag.String("heuristic", "LongestTaskTime", "balancing heuristic")
+		mode      = flag.String("mode", "salbp1", "salbp1 (minimize stations) or salbp2 (minimize cycle time)")
+		nStations = flag.Int("stations", 0, "number of stations on the line (default one per task)")
+		improve   = flag.Bool("improve", false, "improve the balance with shift and swap moves")
+		method    = flag.String("method", "station", "balance method: station, shortest, portfolio, anneal, genetic, tabu, aco, beam or bb (salbp2: station or bb)")
+		objective = flag.String("objective", "stations", "objective for improvement methods: stations or smoothness")
+		seed      = flag.Int64("seed", 1, "random seed for randomized methods")
+		iters     = flag.Int("iterations", 0, "iteration limit for iterative methods, or node limit for bb (default per method)")
+		timeout   = flag.Duration("timeout", 0, "time limit for iterative methods")
+		width     = flag.Int("width", 0, "beam width for the beam method (default 5)")
+		direction = flag.String("direction", "forward", "direction to fill stations: forward, reverse or bidirectional")
+		graph     = flag.Bool("graph", false, "print precedence graph indicators before balancing (text output only)")
+		solution  = flag.String("solution", "", "write the balance to this file as a JSON solution")
+		output    = flag.String("output", "text", "output format: text, json or csv")
 	)
 
 	flag.Parse()
 	log.SetLevel(log.DebugLevel)
 
+	write, ok := outputMap[*output]
+	if !ok {
+		log.Fatalf("balance: unknown output format %q", *output)
+	}
+
 	if *filename == "" {
 		flag.Usage()
 		os.Exit(1)
@@ -30,35 +56,177 @@ func main() {
 
 	line := alb.NewLine(*filename)
 
-	tasks, stations, err := ParseIn2File(input)
+	inst, stations, err := ParseFile(input, fileFormat(*filename, *format))
 	if err != nil {
 		log.Fatalf("balance: %s", err)
 	}
 
-	line.AddTasks(tasks)
+	switch {
+	case *nStations < 0:
+		log.Fatalf("balance: invalid number of stations %d", *nStations)
+	case *nStations == 0:
+	case *nStations >= len(stations):
+		log.WithFields(log.Fields{
+			"stations": *nStations,
+			"tasks":    len(stations),
+		}).Warnf("Number of stations is not below the number of tasks, using one station per task")
+	default:
+		stations = stations[:*nStations]
+	}
+
+	line.AddTasks(inst.Tasks)
 	line.AddStations(stations)
 
-	ctime, err := ValidateLine(line, *cycleTime)
+	if !flagSet("cycle") && len(inst.CycleTimes) > 0 {
+		*cycleTime = inst.CycleTimes[0]
+	}
+
+	err = line.ValidatePrecedence()
 	if err != nil {
 		log.Fatalf("balance: %s", err)
 	}
 
-	constraints := []alb.Constraint{
-		&alb.SingleTaskAssignment{},
-		&alb.RestrictedStationTime{Time: ctime},
-		&alb.PredecessorsStartToStart{},
+	if *graph && *output == "text" {
+		g, err := line.PrecedenceGraph()
+		if err != nil {
+			log.Fatalf("balance: %s", err)
+		}
+		alb.PrintPrecedenceGraph(g)
+	}
+
+	obj, err := stoo(*objective)
+	if err != nil {
+		log.Fatalf("balance: %s", err)
+	}
+
+	line.Direction, err = alb.ParseDirection(*direction)
+	if err != nil {
+		log.Fatalf("balance: %s", err)
+	}
+
+	opts := methodOptions{
+		objective:  obj,
+		seed:       *seed,
+		iterations: *iters,
+		width:      *width,
+		timeout:    *timeout,
+		text:       *output == "text",
+	}
+
+	var ctime float64
+	switch *mode {
+	case "salbp1":
+		ctime, err = ValidateLine(line, *cycleTime)
+		if err != nil {
+			log.Fatalf("balance: %s", err)
+		}
+
+		constraints := []alb.Constraint{
+			&alb.SingleTaskAssignment{},
+			&alb.RestrictedStationTime{Time: ctime},
+			&alb.PredecessorsStartToStart{},
+		}
+		line.AddConstraints(constraints)
+
+		// Balance
+		opts.heuristic = stoh(line, *heuristic)
+		err = balance(line, *method, opts)
+		if err != nil {
+			log.Fatalf("balance: %s", err)
+		}
+	case "salbp2":
+		constraints := []alb.Constraint{
+			&alb.SingleTaskAssignment{},
+			&alb.PredecessorsStartToStart{},
+		}
+		line.AddConstraints(constraints)
+
+		// Balance, searching for the smallest cycle time on the stations
+		opts.heuristic = stoh(line, *heuristic)
+		o, err := oracle(*method, opts)
+		if err != nil {
+			log.Fatalf("balance: %s", err)
+		}
+
+		ctime, err = line.MinimizeCycleTime(o, 0)
+		if err != nil {
+			log.Fatalf("balance: %s", err)
+		}
+	default:
+		log.Fatalf("balance: unknown mode %q", *mode)
+	}
+
+	if *improve {
+		summary, err := line.Improve(obj)
+		if err != nil {
+			log.Fatalf("balance: %s", err)
+		}
+
+		for _, move := range summary.Moves {
+			log.Debugf("improve: %s", move)
+		}
+		log.WithFields(log.Fields{
+			"moves":    len(summary.Moves),
+			"stations": summary.StationsAfter,
+		}).Infof("Improved balance")
 	}
-	line.AddConstraints(constraints)
 
-	// Balance
-	h := stoh(*heuristic)
-	err = line.BalanceByStationId(h)
+	explainFreeTasks(line)
+
+	if *solution != "" {
+		err = writeSolution(*solution, line)
+		if err != nil {
+			log.Fatalf("balance: %s", err)
+		}
+	}
+
+	report := alb.NewReport(line, ctime)
+	flag.VisitAll(func(f *flag.Flag) {
+		report.Params[f.Name] = f.Value.String()
+	})
+
+	err = write(report, os.Stdout)
 	if err != nil {
 		log.Fatalf("balance: %s", err)
 	}
+}
+
+// explainFreeTasks logs why each task left free could not be assigned to
+// the last station in use.
+func explainFreeTasks(line *alb.Line) {
+	stations := line.ActiveStations()
+	if len(stations) == 0 {
+		return
+	}
+
+	last := stations[len(stations)-1]
+	for _, task := range line.FreeTasks() {
+		reasons := line.ExplainAssignment(task.ID, last.ID)
+		log.WithFields(log.Fields{
+			"task":    task.ID,
+			"station": last.ID,
+		}).Warnf("Task not assigned: %s", strings.Join(reasons, "; "))
+	}
+}
+
+// writeSolution writes the line's balance to a file as a JSON solution.
+func writeSolution(filename string, line *alb.Line) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("solution: %s", err)
+	}
+	defer file.Close()
+
+	return line.Solution().Write(file)
+}
 
-	alb.PrintMeasurements(line, ctime)
-	alb.PrintFreeTasks(line)
-	alb.PrintStations(line)
-	alb.PrintTaskVector(line)
+// flagSet reports whether the named flag was given on the command line.
+func flagSet(name string) bool {
+	var set bool
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
 }
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/parallelworks/alb"
+)
+
+var (
+	objectiveMap = map[string]alb.Objective{
+		"stations":   alb.MinimizeStations,
+		"smoothness": alb.MinimizeSmoothness,
+	}
+
+	outputMap = map[string]func(*alb.Report, io.Writer) error{
+		"text": (*alb.Report).WriteText,
+		"json": (*alb.Report).WriteJSON,
+		"csv":  (*alb.Report).WriteCSV,
+	}
+)
+
+// methodOptions holds the settings shared by the balance methods.
+type methodOptions struct {
+	heuristic  alb.Heuristic
+	objective  alb.Objective
+	seed       int64
+	iterations int
+	width      int
+	timeout    time.Duration
+
+	// text is set for text output, where tables such as the portfolio's
+	// runs may be printed before the report.
+	text bool
+}
+
+// limits returns the branch and bound limits given by the iteration and
+// time limits.
+func (opts methodOptions) limits() alb.BranchAndBoundLimits {
+	return alb.BranchAndBoundLimits{Nodes: opts.iterations, Time: opts.timeout}
+}
+
+func stoo(objective string) (alb.Objective, error) {
+	o, ok := objectiveMap[objective]
+	if !ok {
+		return o, fmt.Errorf("unknown objective %q", objective)
+	}
+	return o, nil
+}
+
+// balance balances the line with the named method.
+func balance(line *alb.Line, method string, opts methodOptions) error {
+	switch method {
+	case "station":
+		return line.BalanceByStationId(opts.heuristic)
+	case "shortest":
+		return line.BalanceByShortestStationTime(opts.heuristic)
+	case "portfolio":
+		result, err := line.BalancePortfolio()
+		if err != nil {
+			return err
+		}
+
+		if opts.text {
+			alb.PrintPortfolio(result)
+		}
+		log.WithFields(log.Fields{
+			"heuristic": result.Best.Heuristic,
+			"method":    result.Best.Method,
+			"elapsed":   result.Elapsed,
+		}).Infof("Portfolio balance")
+		return nil
+	case "anneal":
+		result, err := line.BalanceByAnnealing(alb.AnnealOptions{
+			Objective:  opts.objective,
+			Iterations: opts.iterations,
+			TimeLimit:  opts.timeout,
+			Seed:       opts.seed,
+			Heuristic:  opts.heuristic,
+		})
+		if err != nil {
+			return err
+		}
+
+		log.WithFields(log.Fields{
+			"iterations": result.Iterations,
+			"accepted":   result.Accepted,
+			"cost":       result.Cost,
+			"elapsed":    result.Elapsed,
+		}).Infof("Annealed balance")
+		return nil
+	case "genetic":
+		result, err := line.BalanceByGeneticAlgorithm(alb.GeneticOptions{
+			Objective:   opts.objective,
+			Generations: opts.iterations,
+			TimeLimit:   opts.timeout,
+			Seed:        opts.seed,
+		})
+		if err != nil {
+			return err
+		}
+
+		log.WithFields(log.Fields{
+			"generations": result.Generations,
+			"best":        result.BestGeneration,
+			"cost":        result.Cost,
+			"elapsed":     result.Elapsed,
+		}).Infof("Evolved balance")
+		return nil
+	case "tabu":
+		// Tabu search improves an existing balance
+		err := line.BalanceByShortestStationTime(opts.heuristic)
+		if err != nil {
+			return err
+		}
+
+		// The shortest station balance can assign tasks before their
+		// predecessors; tabu search then builds its own starting balance.
+		if err := line.Validate(); err != nil {
+			log.Warnf("Starting balance is invalid, starting from a station balance instead: %s", err)
+			err = line.UnassignTasks()
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err := line.BalanceByTabuSearch(alb.TabuOptions{
+			Objective:  opts.objective,
+			Iterations: opts.iterations,
+			TimeLimit:  opts.timeout,
+			Seed:       opts.seed,
+			Heuristic:  opts.heuristic,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, step := range result.Trace {
+			log.Debugf("tabu: %d: %s (cost %.2f, best %.2f)", step.Iteration, step.Move, step.Cost, step.Best)
+		}
+		log.WithFields(log.Fields{
+			"iterations": result.Iterations,
+			"best":       result.BestIteration,
+			"cost":       result.Cost,
+			"elapsed":    result.Elapsed,
+		}).Infof("Tabu search balance")
+		return nil
+	case "aco":
+		result, err := line.BalanceByAntColony(alb.AntColonyOptions{
+			Objective:  opts.objective,
+			Heuristic:  opts.heuristic,
+			Iterations: opts.iterations,
+			TimeLimit:  opts.timeout,
+			Seed:       opts.seed,
+		})
+		if err != nil {
+			return err
+		}
+
+		log.WithFields(log.Fields{
+			"iterations": result.Iterations,
+			"best":       result.BestIteration,
+			"cost":       result.Cost,
+			"elapsed":    result.Elapsed,
+		}).Infof("Ant colony balance")
+		return nil
+	case "beam":
+		result, err := line.BalanceByBeamSearch(alb.BeamOptions{
+			Width:     opts.width,
+			Heuristic: opts.heuristic,
+			TimeLimit: opts.timeout,
+		})
+		if err != nil {
+			return err
+		}
+
+		log.WithFields(log.Fields{
+			"expanded": result.Expanded,
+			"elapsed":  result.Elapsed,
+		}).Infof("Beam search balance")
+		return nil
+	case "bb":
+		result, err := line.BalanceByBranchAndBound(opts.heuristic, opts.limits())
+		if err != nil {
+			return err
+		}
+
+		log.WithFields(log.Fields{
+			"stations": result.Stations,
+			"optimal":  result.Optimal,
+			"nodes":    result.Nodes,
+			"elapsed":  result.Elapsed,
+		}).Infof("Branch and bound balance")
+		return nil
+	}
+	return fmt.Errorf("unknown method %q", method)
+}
+
+// oracle returns the feasibility oracle of the named method, for
+// minimizing the cycle time. Only the station and bb methods have one.
+func oracle(method string, opts methodOptions) (alb.FeasibilityOracle, error) {
+	switch method {
+	case "station":
+		return alb.HeuristicOracle(opts.heuristic), nil
+	case "bb":
+		return alb.BranchAndBoundOracle(opts.heuristic, opts.limits()), nil
+	}
+	return nil, fmt.Errorf("method %q cannot be used with -mode=salbp2, use station or bb", method)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/parallelworks/alb"
+)
+
+// validate runs the validate subcommand, which checks a balance of a line
+// and prints every violation it finds.
+func validate(args []string) {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	var (
+		filename  = fs.String("file", "", "input file")
+		format    = fs.String("format", "", "input file format: in2, csv or alb (default the file's extension, or in2 without one)")
+		solution  = fs.String("solution", "", "balance to check: a JSON solution, or a CSV file of task,station rows")
+		cycleTime = fs.Float64("cycle", 0, "cycle time of line (default the file's cycle time, or the solution's)")
+	)
+	fs.Parse(args)
+
+	if *filename == "" || *solution == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	input, err := GetStream(*filename)
+	if err != nil {
+		log.Fatalf("validate: %s", err)
+	}
+
+	inst, stations, err := ParseFile(input, fileFormat(*filename, *format))
+	if err != nil {
+		log.Fatalf("validate: %s", err)
+	}
+
+	line := alb.NewLine(*filename)
+	line.AddTasks(inst.Tasks)
+	line.AddStations(stations)
+
+	if *cycleTime <= 0 && len(inst.CycleTimes) > 0 {
+		*cycleTime = inst.CycleTimes[0]
+	}
+
+	err = line.ValidatePrecedence()
+	if err != nil {
+		log.Fatalf("validate: %s", err)
+	}
+	line.AddConstraints([]alb.Constraint{
+		&alb.SingleTaskAssignment{},
+		&alb.PredecessorsStartToStart{},
+		&alb.PredecessorsInPriorStations{},
+	})
+	if *cycleTime > 0 {
+		line.AddConstraint(&alb.RestrictedStationTime{Time: *cycleTime})
+	}
+
+	sol, err := readSolution(*solution)
+	if err != nil {
+		log.Fatalf("validate: %s", err)
+	}
+
+	err = line.ValidateSolution(sol)
+	if verr, ok := err.(*alb.ValidationError); ok {
+		for _, v := range verr.Violations {
+			fmt.Printf("%s\t%s\n", v.Kind, v.Message)
+		}
+		fmt.Printf("violations=%d\n", len(verr.Violations))
+		os.Exit(1)
+	}
+	if err != nil {
+		log.Fatalf("validate: %s", err)
+	}
+
+	fmt.Printf("violations=0\n")
+}
+
+// readSolution reads a solution from a JSON file, or from a CSV file (by
+// its .csv extension) with a task id and station id on each row.
+func readSolution(filename string) (*alb.Solution, error) {
+	input, err := GetStream(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.ToLower(filepath.Ext(filename)) == ".csv" {
+		return readSolutionCSV(input)
+	}
+	return alb.ReadSolution(input)
+}
+
+// readSolutionCSV reads task,station rows into a solution, skipping a header
+// row if there is one.
+func readSolutionCSV(in io.Reader) (*alb.Solution, error) {
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = 2
+	r.TrimLeadingSpace = true
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("solution: %s", err)
+	}
+
+	sol := &alb.Solution{Assignments: make(map[int]int)}
+	for i, row := range rows {
+		taskID, err := strconv.Atoi(row[0])
+		if err != nil && i == 0 {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("solution: line %d: task: %s", i+1, err)
+		}
+
+		stationID, err := strconv.Atoi(row[1])
+		if err != nil {
+			return nil, fmt.Errorf("solution: line %d: station: %s", i+1, err)
+		}
+
+		if _, ok := sol.Assignments[taskID]; ok {
+			return nil, fmt.Errorf("solution: line %d: task %d is assigned twice", i+1, taskID)
+		}
+		sol.Assignments[taskID] = stationID
+	}
+	return sol, nil
+}
@@ -0,0 +1,227 @@
+package alb
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// AntColonyOptions configures BalanceByAntColony. Zero values select the
+// defaults given for each field.
+type AntColonyOptions struct {
+	// Objective is the cost being minimized (default MinimizeStations).
+	Objective Objective
+
+	// Heuristic ranks the candidates at each step to give their visibility
+	// (default LongestTaskTime).
+	Heuristic Heuristic
+
+	// Ants is the number of balances built per iteration (default 10).
+	Ants int
+
+	// Iterations is the number of iterations (default 100).
+	Iterations int
+
+	// TimeLimit stops the search early, if non-zero. At least one
+	// iteration is always run.
+	TimeLimit time.Duration
+
+	// Evaporation is the fraction of pheromone lost each iteration
+	// (default 0.1).
+	Evaporation float64
+
+	// Alpha and Beta weigh pheromone and visibility against each other
+	// (defaults 1 and 2).
+	Alpha float64
+	Beta  float64
+
+	// Seed seeds the ants' choices, so runs with the same seed and line
+	// produce the same balance.
+	Seed int64
+}
+
+// AntColonyResult describes the outcome of BalanceByAntColony.
+type AntColonyResult struct {
+	Stations   int
+	FreeTasks  int
+	Smoothness float64
+	Cost       float64
+
+	Iterations    int
+	BestIteration int
+
+	// Solution is the balance assigned to the line.
+	Solution *Solution
+
+	Elapsed time.Duration
+}
+
+// minPheromone keeps every assignment possible after long evaporation.
+const minPheromone = 0.01
+
+// BalanceByAntColony balances the line by ant colony optimization. Each ant
+// loads the stations in order by id like BalanceByStationId, picking the
+// next task with probability proportional to pheromone^Alpha *
+// visibility^Beta. Pheromone is kept per task and station pair. A task's
+// visibility is 1/r when the heuristic would pick it r-th among the
+// candidates.
+//
+// After each iteration pheromone evaporates, and the pairs used by the
+// iteration's best ant and the best ant so far are reinforced. Balances are
+// ranked by free tasks and then by the objective's cost, and the best
+// balance is assigned to the line when it finishes.
+//
+// Only Forward lines are supported.
+func (l *Line) BalanceByAntColony(opts AntColonyOptions) (*AntColonyResult, error) {
+	start := time.Now()
+	if l.Direction != Forward {
+		return nil, fmt.Errorf("%s balancing is not supported by ant colony optimization", l.Direction)
+	}
+
+	if opts.Objective == (Objective{}) {
+		opts.Objective = MinimizeStations
+	}
+	if opts.Heuristic == nil {
+		opts.Heuristic = LongestTaskTime
+	}
+	if opts.Ants <= 0 {
+		opts.Ants = 10
+	}
+	if opts.Iterations <= 0 {
+		opts.Iterations = 100
+	}
+	if opts.Evaporation <= 0 || opts.Evaporation >= 1 {
+		opts.Evaporation = 0.1
+	}
+	if opts.Alpha <= 0 {
+		opts.Alpha = 1
+	}
+	if opts.Beta <= 0 {
+		opts.Beta = 2
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	// Pairs no ant has used yet share the untouched pheromone level.
+	pheromone := make(map[[2]int]float64)
+	untouched := 1.0
+	trail := func(task *Task, station *Station) float64 {
+		if tau, ok := pheromone[[2]int{task.ID, station.ID}]; ok {
+			return tau
+		}
+		return untouched
+	}
+
+	pick := func(station *Station, candidates []*Task) *Task {
+		ranks := rank(opts.Heuristic, candidates)
+		weights := make([]float64, len(candidates))
+		var total float64
+		for i, task := range candidates {
+			visibility := 1 / float64(ranks[task.ID])
+			weights[i] = math.Pow(trail(task, station), opts.Alpha) * math.Pow(visibility, opts.Beta)
+			total += weights[i]
+		}
+
+		r := rng.Float64() * total
+		for i, w := range weights {
+			r -= w
+			if r < 0 {
+				return candidates[i]
+			}
+		}
+		return candidates[len(candidates)-1]
+	}
+
+	var best *costedBalance
+	result := &AntColonyResult{}
+	for result.Iterations < opts.Iterations {
+		if result.Iterations > 0 && opts.TimeLimit > 0 && time.Since(start) >= opts.TimeLimit {
+			break
+		}
+		result.Iterations++
+
+		var iterationBest *costedBalance
+		for ant := 0; ant < opts.Ants; ant++ {
+			err := l.reset()
+			if err != nil {
+				return nil, err
+			}
+
+			err = l.fillStations(pick)
+			if err != nil {
+				return nil, err
+			}
+
+			b := &costedBalance{
+				assignment: l.Snapshot(),
+				free:       l.NFreeTasks(),
+				cost:       opts.Objective.Cost(l),
+			}
+			if iterationBest == nil || b.better(iterationBest) {
+				iterationBest = b
+			}
+		}
+
+		if best == nil || iterationBest.better(best) {
+			best = iterationBest
+			result.BestIteration = result.Iterations
+		}
+
+		for pair, tau := range pheromone {
+			pheromone[pair] = math.Max(tau*(1-opts.Evaporation), minPheromone)
+		}
+		untouched = math.Max(untouched*(1-opts.Evaporation), minPheromone)
+
+		for _, b := range []*costedBalance{iterationBest, best} {
+			deposit := 1 / (1 + b.cost + float64(b.free*len(l.stations)))
+			for stationID, taskIDs := range b.assignment.tasks {
+				for _, taskID := range taskIDs {
+					pair := [2]int{taskID, stationID}
+					if _, ok := pheromone[pair]; !ok {
+						pheromone[pair] = untouched
+					}
+					pheromone[pair] += deposit
+				}
+			}
+		}
+	}
+
+	if best == nil {
+		return result, nil
+	}
+
+	err := l.Restore(best.assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Stations = l.NActiveStations()
+	result.FreeTasks = best.free
+	result.Smoothness = l.smoothness()
+	result.Cost = best.cost
+	result.Solution = l.solution("aco")
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
+
+// rank returns the position, counting from 1, at which the heuristic picks
+// each candidate when picking repeatedly from them, keyed by task id.
+// Candidates are sorted by asking the heuristic to pick between pairs, so it
+// is called O(k log k) times on two tasks rather than k times on up to k.
+// A candidate it picks from a pair in either order comes first; otherwise
+// the pair keeps its order, as when the heuristic breaks ties by position.
+func rank(fn Heuristic, candidates []*Task) map[int]int {
+	sorted := append([]*Task(nil), candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		return fn([]*Task{a, b}) == a && fn([]*Task{b, a}) == a
+	})
+
+	ranks := make(map[int]int)
+	for i, task := range sorted {
+		ranks[task.ID] = i + 1
+	}
+	return ranks
+}
@@ -0,0 +1,93 @@
+package alb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAntColony(t *testing.T) {
+	line := newTestLine("TestAntColony", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+
+	result, err := line.BalanceByAntColony(AntColonyOptions{Iterations: 30, Seed: 1})
+	if err != nil {
+		t.Fatalf("BalanceByAntColony returned an error, %s", err)
+	}
+
+	if result.Stations != 2 || result.FreeTasks != 0 {
+		t.Errorf("BalanceByAntColony() = 2 stations 0 free, got %d stations %d free", result.Stations, result.FreeTasks)
+	}
+
+	if got := line.NActiveStations(); got != result.Stations {
+		t.Errorf("line.NActiveStations() = %d, got %d", result.Stations, got)
+	}
+}
+
+func TestAntColonyTimeLimit(t *testing.T) {
+	line := newTestLine("TestAntColonyTimeLimit", []float64{5, 4, 3}, nil, 10)
+
+	result, err := line.BalanceByAntColony(AntColonyOptions{TimeLimit: time.Nanosecond, Seed: 1})
+	if err != nil {
+		t.Fatalf("BalanceByAntColony returned an error, %s", err)
+	}
+
+	if result.Iterations != 1 || result.Stations == 0 || line.NFreeTasks() != 0 {
+		t.Errorf("BalanceByAntColony() = 1 iteration with a balance, got %+v", result)
+	}
+}
+
+func TestAntColonySeed(t *testing.T) {
+	preds := [][2]int{{1, 3}, {2, 4}, {3, 5}}
+	times := []float64{5, 4, 3, 3, 3, 2, 6, 1}
+
+	var assignments []*Snapshot
+	for i := 0; i < 2; i++ {
+		line := newTestLine("TestAntColonySeed", times, preds, 10)
+		opts := AntColonyOptions{Objective: MinimizeSmoothness, Ants: 3, Iterations: 5, Seed: 5}
+		_, err := line.BalanceByAntColony(opts)
+		if err != nil {
+			t.Fatalf("BalanceByAntColony returned an error, %s", err)
+		}
+		assignments = append(assignments, line.Snapshot())
+	}
+
+	if !reflect.DeepEqual(assignments[0], assignments[1]) {
+		t.Errorf("BalanceByAntColony(Seed: 5) = %v, got %v", assignments[0], assignments[1])
+	}
+}
+
+func TestRank(t *testing.T) {
+	tasks := []*Task{NewTask(1, 3), NewTask(2, 9), NewTask(3, 5)}
+
+	got := rank(LongestTaskTime, tasks)
+	want := map[int]int{2: 1, 3: 2, 1: 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rank(LongestTaskTime) = %v, got %v", want, got)
+	}
+
+	// Ranks match picking repeatedly, ties included, for every heuristic.
+	line := newTestLine("TestRank", []float64{3, 9, 3, 5, 9, 1}, [][2]int{{1, 4}, {2, 4}, {4, 6}, {3, 5}}, 10)
+	heuristics := map[string]Heuristic{"Priorities": Priorities{1: 1, 2: 2, 3: 1, 4: 2}.Heuristic()}
+	for name, fn := range Heuristics {
+		heuristics[name] = fn
+	}
+
+	for name, fn := range heuristics {
+		want := make(map[int]int)
+		remaining := line.Tasks()
+		for r := 1; len(remaining) > 0; r++ {
+			picked := fn(remaining)
+			want[picked.ID] = r
+			for i, task := range remaining {
+				if task == picked {
+					remaining = append(remaining[:i], remaining[i+1:]...)
+					break
+				}
+			}
+		}
+
+		if got := rank(fn, line.Tasks()); !reflect.DeepEqual(got, want) {
+			t.Errorf("rank(%s) = %v, got %v", name, want, got)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package alb
+
+import (
+	"math/rand"
+	"time"
+)
+
+// ComsoalOptions configures BalanceByComsoal.
+type ComsoalOptions struct {
+	// Iterations is the number of balances to build. If both Iterations
+	// and TimeLimit are zero, 1000 balances are built.
+	Iterations int
+
+	// TimeLimit stops the search after the given duration, if non-zero.
+	// At least one balance is always built.
+	TimeLimit time.Duration
+
+	// Seed seeds the random choices, so runs with the same seed and line
+	// produce the same balance.
+	Seed int64
+
+	// Weights, if set, makes each candidate's chance of being picked
+	// proportional to its weight, e.g. RankedPositionalWeight(line). Tasks
+	// without a positive weight are only picked when no candidate has one.
+	// Otherwise candidates are picked uniformly.
+	Weights Priorities
+}
+
+// ComsoalResult describes the outcome of BalanceByComsoal.
+type ComsoalResult struct {
+	Stations   int
+	FreeTasks  int
+	Smoothness float64
+
+	// Iterations is the number of balances built, and BestIteration the one
+	// that was kept, counting from 1.
+	Iterations    int
+	BestIteration int
+
+	// Solution is the balance assigned to the line.
+	Solution *Solution
+
+	Elapsed time.Duration
+}
+
+// BalanceByComsoal repeatedly balances the line with BalanceByStationId,
+// picking randomly among the valid assignments at each step (COMSOAL). The
+// best balance found (fewest free tasks, then fewest stations, then the
+// lowest smoothness index) is assigned to the line when it finishes.
+func (l *Line) BalanceByComsoal(opts ComsoalOptions) (*ComsoalResult, error) {
+	start := time.Now()
+	if opts.Iterations == 0 && opts.TimeLimit == 0 {
+		opts.Iterations = 1000
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	pick := randomHeuristic(rng, opts.Weights)
+
+	var best *Snapshot
+	var bestScore balanceScore
+	result := &ComsoalResult{}
+	for opts.Iterations == 0 || result.Iterations < opts.Iterations {
+		if result.Iterations > 0 && opts.TimeLimit > 0 && time.Since(start) >= opts.TimeLimit {
+			break
+		}
+
+		err := l.reset()
+		if err != nil {
+			return nil, err
+		}
+
+		err = l.BalanceByStationId(pick)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Iterations++
+		score := l.score()
+		if best == nil || score.less(bestScore) {
+			best = l.Snapshot()
+			bestScore = score
+			result.BestIteration = result.Iterations
+		}
+	}
+
+	err := l.Restore(best)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Stations = bestScore.stations
+	result.FreeTasks = bestScore.free
+	result.Smoothness = bestScore.smoothness
+	result.Solution = l.solution("comsoal")
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
+
+// randomHeuristic returns a heuristic that picks a candidate at random,
+// weighted by the given priorities if they are not nil.
+func randomHeuristic(rng *rand.Rand, weights Priorities) Heuristic {
+	return func(tasks []*Task) *Task {
+		if len(tasks) == 0 {
+			return nil
+		}
+
+		var total float64
+		for _, task := range tasks {
+			if w := weights[task.ID]; w > 0 {
+				total += w
+			}
+		}
+
+		if total == 0 {
+			return tasks[rng.Intn(len(tasks))]
+		}
+
+		r := rng.Float64() * total
+		for _, task := range tasks {
+			if w := weights[task.ID]; w > 0 {
+				r -= w
+				if r < 0 {
+					return task
+				}
+			}
+		}
+
+		// Float error can leave r just above zero after the last weight.
+		for i := len(tasks) - 1; ; i-- {
+			if weights[tasks[i].ID] > 0 {
+				return tasks[i]
+			}
+		}
+	}
+}
+
+// balanceScore ranks balances of a line, see less.
+type balanceScore struct {
+	free       int
+	stations   int
+	smoothness float64
+}
+
+// less reports whether s is a better balance than o: it leaves fewer tasks
+// free, then uses fewer stations, then is smoother.
+func (s balanceScore) less(o balanceScore) bool {
+	if s.free != o.free {
+		return s.free < o.free
+	}
+	if s.stations != o.stations {
+		return s.stations < o.stations
+	}
+	return s.smoothness < o.smoothness-eps
+}
+
+// score returns the score of the line's current balance.
+func (l *Line) score() balanceScore {
+	return balanceScore{
+		free:       l.NFreeTasks(),
+		stations:   l.NActiveStations(),
+		smoothness: l.smoothness(),
+	}
+}
+
+// smoothness returns the smoothness index of the line at its measured cycle
+// time.
+func (l *Line) smoothness() float64 {
+	return SmoothnessIndex(l, l.measuredCycleTime())
+}
+
+// measuredCycleTime returns the line's cycle time, or its longest station
+// time if it has no cycle time.
+func (l *Line) measuredCycleTime() float64 {
+	time, ok := l.CycleTime()
+	if !ok {
+		time = l.maxStationTime()
+	}
+	return time
+}
+
+// maxStationTime returns the longest time of the line's active stations.
+func (l *Line) maxStationTime() float64 {
+	var time float64
+	for _, station := range l.stations {
+		if station.Active() && station.Time() > time {
+			time = station.Time()
+		}
+	}
+	return time
+}
@@ -0,0 +1,65 @@
+package alb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestComsoal(t *testing.T) {
+	line := newTestLine("TestComsoal", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+
+	result, err := line.BalanceByComsoal(ComsoalOptions{Iterations: 200, Seed: 1})
+	if err != nil {
+		t.Fatalf("BalanceByComsoal returned an error, %s", err)
+	}
+
+	if result.Stations != 2 || result.FreeTasks != 0 {
+		t.Errorf("BalanceByComsoal() = 2 stations 0 free, got %d stations %d free", result.Stations, result.FreeTasks)
+	}
+
+	if got := line.NActiveStations(); got != result.Stations {
+		t.Errorf("line.NActiveStations() = %d, got %d", result.Stations, got)
+	}
+
+	if result.Iterations != 200 || result.BestIteration < 1 || result.BestIteration > 200 {
+		t.Errorf("BalanceByComsoal() ran %d iterations, best %d", result.Iterations, result.BestIteration)
+	}
+}
+
+func TestComsoalSeed(t *testing.T) {
+	preds := [][2]int{{1, 3}, {2, 4}, {3, 5}}
+	times := []float64{5, 4, 3, 3, 3, 2, 6, 1}
+
+	var assignments []*Snapshot
+	for i := 0; i < 2; i++ {
+		line := newTestLine("TestComsoalSeed", times, preds, 10)
+		weights := RankedPositionalWeight(line)
+		_, err := line.BalanceByComsoal(ComsoalOptions{Iterations: 5, Seed: 42, Weights: weights})
+		if err != nil {
+			t.Fatalf("BalanceByComsoal returned an error, %s", err)
+		}
+		assignments = append(assignments, line.Snapshot())
+	}
+
+	if !reflect.DeepEqual(assignments[0], assignments[1]) {
+		t.Errorf("BalanceByComsoal(Seed: 42) = %v, got %v", assignments[0], assignments[1])
+	}
+}
+
+func TestComsoalTimeLimit(t *testing.T) {
+	line := newTestLine("TestComsoalTimeLimit", []float64{5, 4, 3}, nil, 10)
+
+	result, err := line.BalanceByComsoal(ComsoalOptions{TimeLimit: time.Nanosecond, Iterations: 1000000})
+	if err != nil {
+		t.Fatalf("BalanceByComsoal returned an error, %s", err)
+	}
+
+	if result.Iterations < 1 || result.Stations == 0 || result.FreeTasks != line.NFreeTasks() {
+		t.Errorf("BalanceByComsoal() = at least 1 iteration and station, got %+v", result)
+	}
+
+	if got := line.NFreeTasks(); got != 0 {
+		t.Errorf("line.NFreeTasks() = 0, got %d", got)
+	}
+}
@@ -1,9 +1,27 @@
 package alb
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Constraint interface {
 	Valid(*Task, *Station) bool
 }
 
+// Parameterized is implemented by constraints with parameters, so that line
+// definitions can write them (see ConstraintTypes).
+type Parameterized interface {
+	Params() map[string]float64
+}
+
+// Explainer is implemented by constraints that can say why an assignment is
+// invalid. Explain returns the reason the task cannot be assigned to the
+// station, or an empty string if it can.
+type Explainer interface {
+	Explain(*Task, *Station) string
+}
+
 type OnlyActiveStations struct {
 }
 
@@ -11,6 +29,13 @@ func (c *OnlyActiveStations) Valid(task *Task, station *Station) bool {
 	return station.Active()
 }
 
+func (c *OnlyActiveStations) Explain(task *Task, station *Station) string {
+	if c.Valid(task, station) {
+		return ""
+	}
+	return fmt.Sprintf("station %d is not active", station.ID)
+}
+
 type SingleTaskAssignment struct {
 }
 
@@ -18,6 +43,13 @@ func (c *SingleTaskAssignment) Valid(task *Task, station *Station) bool {
 	return !task.IsAssigned()
 }
 
+func (c *SingleTaskAssignment) Explain(task *Task, station *Station) string {
+	if c.Valid(task, station) {
+		return ""
+	}
+	return fmt.Sprintf("task %d already assigned to station %d", task.ID, task.Assignment().ID)
+}
+
 type RestrictedStationTime struct {
 	Time float64
 }
@@ -26,6 +58,17 @@ func (c *RestrictedStationTime) Valid(task *Task, station *Station) bool {
 	return task.Time()+station.Time() <= c.Time
 }
 
+func (c *RestrictedStationTime) Explain(task *Task, station *Station) string {
+	if c.Valid(task, station) {
+		return ""
+	}
+	return fmt.Sprintf("station time %g+%g exceeds %g", station.Time(), task.Time(), c.Time)
+}
+
+func (c *RestrictedStationTime) Params() map[string]float64 {
+	return map[string]float64{"time": c.Time}
+}
+
 type PacedLine struct {
 	Time float64
 }
@@ -34,6 +77,17 @@ func (c *PacedLine) Valid(task *Task, station *Station) bool {
 	return task.Time() <= c.Time
 }
 
+func (c *PacedLine) Explain(task *Task, station *Station) string {
+	if c.Valid(task, station) {
+		return ""
+	}
+	return fmt.Sprintf("task time %g exceeds %g", task.Time(), c.Time)
+}
+
+func (c *PacedLine) Params() map[string]float64 {
+	return map[string]float64{"time": c.Time}
+}
+
 type PredecessorsStartToStart struct {
 }
 
@@ -47,3 +101,41 @@ func (c *PredecessorsStartToStart) Valid(task *Task, station *Station) bool {
 
 	return true
 }
+
+func (c *PredecessorsStartToStart) Explain(task *Task, station *Station) string {
+	var reasons []string
+	for _, pred := range task.Preds() {
+		if !pred.IsAssigned() {
+			reasons = append(reasons, fmt.Sprintf("predecessor %d unassigned", pred.ID))
+		}
+	}
+	return strings.Join(reasons, ", ")
+}
+
+type PredecessorsInPriorStations struct {
+}
+
+func (c *PredecessorsInPriorStations) Valid(task *Task, station *Station) bool {
+	preds := task.Preds()
+	for _, pred := range preds {
+		assignment := pred.Assignment()
+		if assignment == nil || assignment.ID > station.ID {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *PredecessorsInPriorStations) Explain(task *Task, station *Station) string {
+	var reasons []string
+	for _, pred := range task.Preds() {
+		assignment := pred.Assignment()
+		if assignment == nil {
+			reasons = append(reasons, fmt.Sprintf("predecessor %d unassigned", pred.ID))
+		} else if assignment.ID > station.ID {
+			reasons = append(reasons, fmt.Sprintf("predecessor %d at later station %d", pred.ID, assignment.ID))
+		}
+	}
+	return strings.Join(reasons, ", ")
+}
@@ -0,0 +1,193 @@
+package alb
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReadCSV reads an instance from CSV with one task per row:
+//
+//	<id>,<time>,<predecessors>[,<name>[,<attributes>]]
+//
+// as in specs/buxey.csv. Predecessors are task ids separated by spaces, or
+// "nil" (or empty) for none, and may refer to tasks on later rows.
+// Attributes are key=value pairs separated by semicolons. A first row whose
+// id is not a number is taken as a header, and rows starting with '#' are
+// comments. Errors are *ParseErrors giving the line of the file.
+func ReadCSV(r io.Reader) (*Instance, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+
+	inst := NewInstance("")
+	byID := make(map[int]*Task)
+	preds := make(map[*Task][]string)
+	rows := make(map[*Task]int)
+
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var perr *csv.ParseError
+			if errors.As(err, &perr) {
+				return nil, &ParseError{Format: "csv", Line: perr.Line, Err: perr.Err}
+			}
+			return nil, fmt.Errorf("csv: %s", err)
+		}
+
+		row, _ := cr.FieldPos(0)
+		fail := func(format string, args ...interface{}) error {
+			return &ParseError{Format: "csv", Line: row, Err: fmt.Errorf(format, args...)}
+		}
+
+		id, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil && first {
+			continue
+		}
+		if err != nil {
+			return nil, fail("task id: invalid id %q", record[0])
+		}
+
+		if len(record) < 3 || len(record) > 5 {
+			return nil, fail("task %d: expected 3 to 5 fields, got %d", id, len(record))
+		}
+
+		time, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil || time < 0 {
+			return nil, fail("task %d: invalid time %q", id, record[1])
+		}
+
+		if _, ok := byID[id]; ok {
+			return nil, fail("task %d: duplicate task", id)
+		}
+
+		task := NewTask(id, time)
+		if len(record) > 3 {
+			task.Name = record[3]
+		}
+		if len(record) > 4 {
+			task.Attributes, err = parseAttributes(record[4])
+			if err != nil {
+				return nil, fail("task %d: %s", id, err)
+			}
+		}
+
+		byID[id] = task
+		preds[task] = strings.Fields(record[2])
+		rows[task] = row
+		inst.Tasks = append(inst.Tasks, task)
+	}
+
+	for _, task := range inst.Tasks {
+		for _, field := range preds[task] {
+			if field == "nil" {
+				continue
+			}
+
+			predID, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, &ParseError{Format: "csv", Line: rows[task],
+					Err: fmt.Errorf("task %d: invalid predecessor %q", task.ID, field)}
+			}
+
+			pred, ok := byID[predID]
+			if !ok {
+				return nil, &ParseError{Format: "csv", Line: rows[task],
+					Err: fmt.Errorf("task %d: unknown predecessor %d", task.ID, predID)}
+			}
+			task.AddPred(pred)
+		}
+	}
+
+	if len(inst.Tasks) == 0 {
+		return nil, errors.New("csv: file has no tasks")
+	}
+	return inst, nil
+}
+
+// parseAttributes parses key=value pairs separated by semicolons.
+func parseAttributes(field string) (map[string]string, error) {
+	attrs := make(map[string]string)
+	for _, pair := range strings.Split(field, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		i := strings.IndexByte(pair, '=')
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid attribute %q, expected key=value", pair)
+		}
+		attrs[strings.TrimSpace(pair[:i])] = strings.TrimSpace(pair[i+1:])
+	}
+	return attrs, nil
+}
+
+// WriteCSV writes the line's tasks in the CSV format read by ReadCSV, in
+// order by id. The name and attributes columns are only written if a task
+// has a name or attributes.
+func WriteCSV(w io.Writer, line *Line) error {
+	tasks := line.Tasks()
+	var described bool
+	for _, task := range tasks {
+		if task.Name != "" || len(task.Attributes) > 0 {
+			described = true
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	for _, task := range tasks {
+		preds := "nil"
+		if ps := task.Preds(); len(ps) > 0 {
+			ids := make([]string, len(ps))
+			for i, pred := range ps {
+				ids[i] = strconv.Itoa(pred.ID)
+			}
+			preds = strings.Join(ids, " ")
+		}
+
+		record := []string{strconv.Itoa(task.ID), formatTime(task.Time()), preds}
+		if described {
+			record = append(record, task.Name, formatAttributes(task.Attributes))
+		}
+
+		err := cw.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatTime formats a task time with at least one decimal, e.g. 7.0.
+func formatTime(time float64) string {
+	s := strconv.FormatFloat(time, 'f', -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
+
+func formatAttributes(attrs map[string]string) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = k + "=" + attrs[k]
+	}
+	return strings.Join(pairs, ";")
+}
@@ -0,0 +1,122 @@
+package alb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	csvFile, err := os.Open("specs/buxey.csv")
+	if err != nil {
+		t.Fatalf("opening buxey.csv returned an error, %s", err)
+	}
+	defer csvFile.Close()
+
+	in2File, err := os.Open("specs/buxey.in2")
+	if err != nil {
+		t.Fatalf("opening buxey.in2 returned an error, %s", err)
+	}
+	defer in2File.Close()
+
+	inst, err := ReadCSV(csvFile)
+	if err != nil {
+		t.Fatalf("ReadCSV returned an error, %s", err)
+	}
+
+	want, err := ReadIn2(in2File)
+	if err != nil {
+		t.Fatalf("ReadIn2 returned an error, %s", err)
+	}
+
+	if len(inst.Tasks) != len(want.Tasks) {
+		t.Fatalf("ReadCSV(buxey.csv) = %d tasks, got %d", len(want.Tasks), len(inst.Tasks))
+	}
+
+	for i, task := range want.Tasks {
+		got := inst.Tasks[i]
+		if got.ID != task.ID || got.Time() != task.Time() || len(got.Preds()) != len(task.Preds()) {
+			t.Errorf("task %d = time %.2f %d preds, got task %d time %.2f %d preds",
+				task.ID, task.Time(), len(task.Preds()), got.ID, got.Time(), len(got.Preds()))
+		}
+	}
+}
+
+func TestReadCSVColumns(t *testing.T) {
+	input := "id,time,preds,name,attributes\n" +
+		"# comment\n" +
+		"2,4,1,Bolt door,zone=A; tool = wrench\n" +
+		"1,3.5,nil,Hang door\n"
+
+	inst, err := ReadCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadCSV returned an error, %s", err)
+	}
+
+	bolt, hang := inst.Tasks[0], inst.Tasks[1]
+	if bolt.Pred(1) != hang || hang.Time() != 3.5 {
+		t.Errorf("task 2 pred 1 = task 1, got %v", bolt.Pred(1))
+	}
+
+	if bolt.Name != "Bolt door" || bolt.Attributes["zone"] != "A" || bolt.Attributes["tool"] != "wrench" {
+		t.Errorf("task 2 = Bolt door zone=A tool=wrench, got %s %v", bolt.Name, bolt.Attributes)
+	}
+
+	var tests = []struct {
+		input string
+		line  int
+	}{
+		{"1,x,nil\n", 1},
+		{"1,1,nil\n2,1,3\n", 2},
+		{"1,1,nil\n\n1,2,nil\n", 3},
+		{"1,1\n", 1},
+		{"1,1,nil,a,b\n", 1},
+		{"1,1,\"nil\n", 1},
+	}
+
+	for _, test := range tests {
+		_, err := ReadCSV(strings.NewReader(test.input))
+		perr, ok := err.(*ParseError)
+		if !ok || perr.Line != test.line {
+			t.Errorf("ReadCSV(%q) = error at line %d, got %v", test.input, test.line, err)
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	want, err := ioutil.ReadFile("specs/buxey.csv")
+	if err != nil {
+		t.Fatalf("reading buxey.csv returned an error, %s", err)
+	}
+
+	inst, err := ReadCSV(bytes.NewReader(want))
+	if err != nil {
+		t.Fatalf("ReadCSV returned an error, %s", err)
+	}
+
+	line, err := inst.Line()
+	if err != nil {
+		t.Fatalf("inst.Line() returned an error, %s", err)
+	}
+
+	var buf bytes.Buffer
+	err = WriteCSV(&buf, line)
+	if err != nil {
+		t.Fatalf("WriteCSV returned an error, %s", err)
+	}
+
+	if buf.String() != string(want) {
+		t.Errorf("WriteCSV(ReadCSV(buxey.csv)) = buxey.csv, got\n%s", buf.String())
+	}
+
+	line.Task(1).Name = "first"
+	line.Task(1).Attributes = map[string]string{"b": "2", "a": "1"}
+	buf.Reset()
+	_ = WriteCSV(&buf, line)
+	rows := strings.Split(buf.String(), "\n")
+	if rows[0] != "1,7.0,nil,first,a=1;b=2" || rows[1] != "2,19.0,nil,," {
+		t.Errorf("WriteCSV() rows = 1,7.0,nil,first,a=1;b=2 and 2,19.0,nil,,, got %q and %q", rows[0], rows[1])
+	}
+}
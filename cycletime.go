@@ -0,0 +1,138 @@
+package alb
+
+import (
+	"errors"
+	"math"
+)
+
+// FeasibilityOracle balances the line under its current constraints and
+// reports whether every task could be assigned to the line's stations.
+type FeasibilityOracle func(*Line) (bool, error)
+
+// HeuristicOracle returns an oracle that balances the line with
+// BalanceByStationId and the given heuristic. It is fast, but may report a
+// cycle time as infeasible when a better balance exists.
+func HeuristicOracle(fn Heuristic) FeasibilityOracle {
+	return func(l *Line) (bool, error) {
+		err := l.reset()
+		if err != nil {
+			return false, err
+		}
+
+		err = l.BalanceByStationId(fn)
+		if err != nil {
+			return false, err
+		}
+		return l.NFreeTasks() == 0, nil
+	}
+}
+
+// BranchAndBoundOracle returns an oracle that balances the line with
+// BalanceByBranchAndBound. Within its limits it answers exactly.
+func BranchAndBoundOracle(fn Heuristic, limits BranchAndBoundLimits) FeasibilityOracle {
+	return func(l *Line) (bool, error) {
+		result, err := l.BalanceByBranchAndBound(fn, limits)
+		if err != nil {
+			return false, err
+		}
+		return result.Stations > 0, nil
+	}
+}
+
+// MinimizeCycleTime finds the smallest cycle time for which the oracle can
+// balance the line on its existing stations (SALBP-2). The line's
+// RestrictedStationTime constraint is set to each candidate cycle time in
+// turn, and is added if the line does not have one.
+//
+// When all task times are integral the search is over integer cycle times.
+// Otherwise it bisects until the cycle time is within tolerance of the
+// optimum for the oracle. It returns the cycle time the resulting balance
+// reaches, its longest station time, and on return the line is balanced
+// with its RestrictedStationTime set to it.
+func (l *Line) MinimizeCycleTime(oracle FeasibilityOracle, tolerance float64) (float64, error) {
+	n := l.NStations()
+	if n == 0 || len(l.tasks) == 0 {
+		return 0, errors.New("cycle time: line needs tasks and stations")
+	}
+
+	if tolerance <= 0 {
+		tolerance = 1e-3
+	}
+
+	// Every station can hold at most one cycle of work and no task can be
+	// split, so the cycle time is at least the larger of the average station
+	// load and the longest task.
+	total := l.TaskTime()
+	lo := total / float64(n)
+	integral := true
+	for _, task := range l.tasks {
+		lo = math.Max(lo, task.Time())
+		if task.Time() != math.Trunc(task.Time()) {
+			integral = false
+		}
+	}
+
+	feasible := func(time float64) (bool, error) {
+		l.setCycleTime(time)
+		return oracle(l)
+	}
+
+	hi := total
+	ok, err := feasible(hi)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, errors.New("cycle time: line cannot be balanced at any cycle time")
+	}
+
+	if integral {
+		lo = math.Ceil(lo)
+		for lo < hi {
+			mid := math.Floor((lo + hi) / 2)
+			ok, err := feasible(mid)
+			if err != nil {
+				return 0, err
+			}
+
+			if ok {
+				hi = mid
+			} else {
+				lo = mid + 1
+			}
+		}
+	} else {
+		for hi-lo > tolerance {
+			mid := (lo + hi) / 2
+			ok, err := feasible(mid)
+			if err != nil {
+				return 0, err
+			}
+
+			if ok {
+				hi = mid
+			} else {
+				lo = mid
+			}
+		}
+	}
+
+	// Leave the line balanced at the best cycle time found.
+	_, err = feasible(hi)
+	if err != nil {
+		return 0, err
+	}
+
+	time := l.maxStationTime()
+	l.setCycleTime(time)
+	return time, nil
+}
+
+// setCycleTime replaces the line's RestrictedStationTime constraint, or adds
+// one if the line does not have it.
+func (l *Line) setCycleTime(time float64) {
+	c := &RestrictedStationTime{Time: time}
+	if l.ReplaceConstraint(c) != nil {
+		l.AddConstraint(c)
+	}
+}
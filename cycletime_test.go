@@ -0,0 +1,46 @@
+package alb
+
+import "testing"
+
+func TestMinimizeCycleTime(t *testing.T) {
+	var tests = []struct {
+		times    []float64
+		preds    [][2]int
+		stations int
+		want     float64
+	}{
+		{[]float64{5, 4, 3, 3, 3, 2}, nil, 2, 10},
+		{[]float64{5, 4, 3, 3, 3, 2}, nil, 6, 5},
+		{[]float64{6, 6, 6}, [][2]int{{1, 2}, {2, 3}}, 2, 12},
+		{[]float64{2.5, 2.5, 1}, nil, 2, 3.5},
+	}
+
+	for _, test := range tests {
+		line := newTestLine("TestMinimizeCycleTime", test.times, test.preds, 0)
+		line.stations = make(map[int]*Station)
+		for i := 1; i <= test.stations; i++ {
+			_ = line.AddStation(NewStation(i))
+		}
+
+		oracle := BranchAndBoundOracle(LongestTaskTime, BranchAndBoundLimits{})
+		got, err := line.MinimizeCycleTime(oracle, 1e-6)
+		if err != nil {
+			t.Errorf("MinimizeCycleTime(%v) returned an error, %s", test.times, err)
+			continue
+		}
+
+		// The cycle time is the balance's longest station time, not the
+		// end of the bisection.
+		if got != test.want {
+			t.Errorf("MinimizeCycleTime(%v) = %.2f, got %.6f", test.times, test.want, got)
+		}
+
+		if c, _ := line.CycleTime(); c != got {
+			t.Errorf("line.CycleTime() after MinimizeCycleTime(%v) = %.2f, got %.6f", test.times, got, c)
+		}
+
+		if n := line.NFreeTasks(); n != 0 {
+			t.Errorf("MinimizeCycleTime(%v) left %d free tasks", test.times, n)
+		}
+	}
+}
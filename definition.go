@@ -0,0 +1,288 @@
+package alb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v2"
+)
+
+// LineDefinition is a line written out as JSON or YAML, e.g.
+//
+//	name: Door Assembly
+//	tasks:
+//	  - {id: 1, time: 7}
+//	  - {id: 2, time: 10, preds: [1], name: Hang door}
+//	stations:
+//	  - {id: 1, tasks: [1]}
+//	  - {id: 2}
+//	constraints:
+//	  - type: SingleTaskAssignment
+//	  - type: RestrictedStationTime
+//	    params: {time: 20}
+//
+// Stations may be left out, giving one station per task numbered from 1.
+// Tasks listed on a station are pre-assigned to it, in order, and the
+// station is activated. Pre-assignments must satisfy the constraints and
+// precedence relations, so a pre-assigned task's predecessors must be
+// pre-assigned too.
+type LineDefinition struct {
+	Name        string                 `json:"name" yaml:"name"`
+	Direction   string                 `json:"direction,omitempty" yaml:"direction,omitempty"`
+	Tasks       []TaskDefinition       `json:"tasks" yaml:"tasks"`
+	Stations    []StationDefinition    `json:"stations,omitempty" yaml:"stations,omitempty"`
+	Constraints []ConstraintDefinition `json:"constraints,omitempty" yaml:"constraints,omitempty"`
+}
+
+// TaskDefinition defines a task and its predecessors by id.
+type TaskDefinition struct {
+	ID         int               `json:"id" yaml:"id"`
+	Time       float64           `json:"time" yaml:"time"`
+	Preds      []int             `json:"preds,omitempty" yaml:"preds,omitempty"`
+	Name       string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+}
+
+// StationDefinition defines a station and the tasks pre-assigned to it.
+type StationDefinition struct {
+	ID    int   `json:"id" yaml:"id"`
+	Tasks []int `json:"tasks,omitempty" yaml:"tasks,omitempty"`
+}
+
+// ConstraintDefinition defines a constraint by its type name in
+// ConstraintTypes and its parameters.
+type ConstraintDefinition struct {
+	Type   string             `json:"type" yaml:"type"`
+	Params map[string]float64 `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+// ConstraintFactory builds a constraint from the parameters given in a line
+// definition.
+type ConstraintFactory func(params map[string]float64) (Constraint, error)
+
+// ConstraintTypes are the constraint types line definitions can use, by the
+// name of their Go type. Add a custom constraint type to it to use it in
+// definitions; to write it, it must also implement Parameterized if it has
+// parameters.
+var ConstraintTypes = map[string]ConstraintFactory{
+	"OnlyActiveStations": func(map[string]float64) (Constraint, error) {
+		return &OnlyActiveStations{}, nil
+	},
+	"SingleTaskAssignment": func(map[string]float64) (Constraint, error) {
+		return &SingleTaskAssignment{}, nil
+	},
+	"RestrictedStationTime": func(params map[string]float64) (Constraint, error) {
+		time, err := timeParam(params)
+		return &RestrictedStationTime{Time: time}, err
+	},
+	"PacedLine": func(params map[string]float64) (Constraint, error) {
+		time, err := timeParam(params)
+		return &PacedLine{Time: time}, err
+	},
+	"PredecessorsStartToStart": func(map[string]float64) (Constraint, error) {
+		return &PredecessorsStartToStart{}, nil
+	},
+	"PredecessorsInPriorStations": func(map[string]float64) (Constraint, error) {
+		return &PredecessorsInPriorStations{}, nil
+	},
+}
+
+// timeParam returns the positive "time" parameter of a constraint.
+func timeParam(params map[string]float64) (float64, error) {
+	time, ok := params["time"]
+	if !ok || time <= 0 {
+		return 0, fmt.Errorf("expected a positive time parameter, got %v", params)
+	}
+	return time, nil
+}
+
+// Line builds the line the definition describes, with its constraints and
+// pre-assignments. If its precedence relations have a cycle or a self-loop,
+// it returns a *PrecedenceError (see ValidatePrecedence). If the
+// pre-assignments violate the line's constraints or precedence relations,
+// it returns a *ValidationError listing the violations (see Violations).
+func (d *LineDefinition) Line() (*Line, error) {
+	line := NewLine(d.Name)
+	if d.Direction != "" {
+		direction, err := ParseDirection(d.Direction)
+		if err != nil {
+			return nil, fmt.Errorf("definition: %s", err)
+		}
+		line.Direction = direction
+	}
+
+	for _, td := range d.Tasks {
+		task := NewTask(td.ID, td.Time)
+		task.Name = td.Name
+		task.Attributes = td.Attributes
+		err := line.AddTask(task)
+		if err != nil {
+			return nil, fmt.Errorf("definition: %s", err)
+		}
+	}
+
+	for _, td := range d.Tasks {
+		for _, id := range td.Preds {
+			pred := line.Task(id)
+			if pred == nil {
+				return nil, fmt.Errorf("definition: task %d: unknown predecessor %d", td.ID, id)
+			}
+			line.Task(td.ID).AddPred(pred)
+		}
+	}
+
+	err := line.ValidatePrecedence()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cd := range d.Constraints {
+		factory, ok := ConstraintTypes[cd.Type]
+		if !ok {
+			return nil, fmt.Errorf("definition: unknown constraint type %q", cd.Type)
+		}
+
+		c, err := factory(cd.Params)
+		if err != nil {
+			return nil, fmt.Errorf("definition: constraint %s: %s", cd.Type, err)
+		}
+		line.AddConstraint(c)
+	}
+
+	stations := d.Stations
+	if len(stations) == 0 {
+		for i := range d.Tasks {
+			stations = append(stations, StationDefinition{ID: i + 1})
+		}
+	}
+
+	for _, sd := range stations {
+		station := NewStation(sd.ID)
+		err := line.AddStation(station)
+		if err != nil {
+			return nil, fmt.Errorf("definition: %s", err)
+		}
+
+		for _, id := range sd.Tasks {
+			task := line.Task(id)
+			if task == nil {
+				return nil, fmt.Errorf("definition: station %d: unknown task %d", sd.ID, id)
+			}
+			if task.IsAssigned() {
+				return nil, fmt.Errorf("definition: station %d: task %d already assigned to station %d",
+					sd.ID, id, task.Assignment().ID)
+			}
+
+			err := station.AssignTask(task)
+			if err != nil {
+				return nil, fmt.Errorf("definition: %s", err)
+			}
+		}
+
+		if station.NTasks() > 0 {
+			station.Activate()
+		}
+	}
+
+	if violations := line.assignmentViolations(); len(violations) > 0 {
+		return nil, &ValidationError{Violations: violations}
+	}
+	return line, nil
+}
+
+// Definition returns a definition of the line, including its current
+// assignment as pre-assignments. Every constraint on the line must be of a
+// type in ConstraintTypes, and every predecessor of a task must be on the
+// line.
+func (l *Line) Definition() (*LineDefinition, error) {
+	d := &LineDefinition{Name: l.Name}
+	if l.Direction != Forward {
+		d.Direction = l.Direction.String()
+	}
+
+	for _, task := range l.Tasks() {
+		td := TaskDefinition{
+			ID:         task.ID,
+			Time:       task.Time(),
+			Name:       task.Name,
+			Attributes: task.Attributes,
+		}
+		for _, pred := range task.Preds() {
+			if l.tasks[pred.ID] != pred {
+				return nil, fmt.Errorf("definition: task %d: predecessor %d is not on the line", task.ID, pred.ID)
+			}
+			td.Preds = append(td.Preds, pred.ID)
+		}
+		d.Tasks = append(d.Tasks, td)
+	}
+
+	for _, station := range l.Stations() {
+		sd := StationDefinition{ID: station.ID}
+		for _, task := range station.Tasks() {
+			sd.Tasks = append(sd.Tasks, task.ID)
+		}
+		d.Stations = append(d.Stations, sd)
+	}
+
+	for _, c := range l.constraints {
+		name := constraintName(c)
+		if _, ok := ConstraintTypes[name]; !ok {
+			return nil, fmt.Errorf("definition: constraint type %q is not in ConstraintTypes", name)
+		}
+
+		cd := ConstraintDefinition{Type: name}
+		if p, ok := c.(Parameterized); ok {
+			cd.Params = p.Params()
+		}
+		d.Constraints = append(d.Constraints, cd)
+	}
+
+	return d, nil
+}
+
+// ReadDefinitionJSON reads a line definition from JSON.
+func ReadDefinitionJSON(r io.Reader) (*LineDefinition, error) {
+	d := &LineDefinition{}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(d)
+	if err != nil {
+		return nil, fmt.Errorf("definition: %s", err)
+	}
+	return d, nil
+}
+
+// ReadDefinitionYAML reads a line definition from YAML.
+func ReadDefinitionYAML(r io.Reader) (*LineDefinition, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("definition: %s", err)
+	}
+
+	d := &LineDefinition{}
+	err = yaml.UnmarshalStrict(data, d)
+	if err != nil {
+		return nil, fmt.Errorf("definition: %s", err)
+	}
+	return d, nil
+}
+
+// WriteJSON writes the definition as indented JSON.
+func (d *LineDefinition) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// WriteYAML writes the definition as YAML.
+func (d *LineDefinition) WriteYAML(w io.Writer) error {
+	data, err := yaml.Marshal(d)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,176 @@
+package alb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadDefinitionYAML(t *testing.T) {
+	input := `name: Door Assembly
+direction: reverse
+tasks:
+  - {id: 1, time: 7}
+  - {id: 2, time: 10, preds: [1], name: Hang door, attributes: {zone: A}}
+  - {id: 3, time: 4, preds: [1]}
+stations:
+  - {id: 1, tasks: [1]}
+  - {id: 2}
+constraints:
+  - type: SingleTaskAssignment
+  - type: RestrictedStationTime
+    params: {time: 20}
+`
+
+	def, err := ReadDefinitionYAML(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadDefinitionYAML returned an error, %s", err)
+	}
+
+	line, err := def.Line()
+	if err != nil {
+		t.Fatalf("def.Line() returned an error, %s", err)
+	}
+
+	if line.Direction != Reverse || len(line.Tasks()) != 3 || line.NStations() != 2 {
+		t.Errorf("line = reverse 3 tasks 2 stations, got %s %d tasks %d stations",
+			line.Direction, len(line.Tasks()), line.NStations())
+	}
+
+	hang := line.Task(2)
+	if hang.Name != "Hang door" || hang.Attributes["zone"] != "A" || hang.Pred(1) == nil {
+		t.Errorf("task 2 = Hang door zone A pred 1, got %q %v %v", hang.Name, hang.Attributes, hang.Preds())
+	}
+
+	station := line.Station(1)
+	if !station.Active() || station.Task(1) == nil || line.NFreeTasks() != 2 {
+		t.Errorf("station 1 = active with task 1 and 2 free tasks, got %t %v %d",
+			station.Active(), station.Tasks(), line.NFreeTasks())
+	}
+
+	// Task 3 fits next to task 1 at station 1, and task 1 is already assigned.
+	if reasons := line.ExplainAssignment(3, 1); len(reasons) != 0 {
+		t.Errorf("line.ExplainAssignment(3, 1) = no reasons, got %v", reasons)
+	}
+	if reasons := line.ExplainAssignment(1, 2); len(reasons) != 1 {
+		t.Errorf("line.ExplainAssignment(1, 2) = 1 reason, got %v", reasons)
+	}
+}
+
+func TestDefinitionRoundTrip(t *testing.T) {
+	line := newTestLine("TestDefinitionRoundTrip", []float64{5, 4, 3}, [][2]int{{1, 2}, {1, 3}}, 10)
+	line.Task(1).Name = "Weld"
+	err := line.Station(1).AssignTask(line.Task(1))
+	if err != nil {
+		t.Fatalf("AssignTask returned an error, %s", err)
+	}
+	line.Station(1).Activate()
+
+	def, err := line.Definition()
+	if err != nil {
+		t.Fatalf("line.Definition() returned an error, %s", err)
+	}
+
+	writers := map[string]func(*bytes.Buffer) (*LineDefinition, error){
+		"json": func(buf *bytes.Buffer) (*LineDefinition, error) {
+			if err := def.WriteJSON(buf); err != nil {
+				return nil, err
+			}
+			return ReadDefinitionJSON(buf)
+		},
+		"yaml": func(buf *bytes.Buffer) (*LineDefinition, error) {
+			if err := def.WriteYAML(buf); err != nil {
+				return nil, err
+			}
+			return ReadDefinitionYAML(buf)
+		},
+	}
+
+	for format, roundTrip := range writers {
+		got, err := roundTrip(&bytes.Buffer{})
+		if err != nil {
+			t.Fatalf("%s round trip returned an error, %s", format, err)
+		}
+
+		copy, err := got.Line()
+		if err != nil {
+			t.Fatalf("%s: Line() returned an error, %s", format, err)
+		}
+
+		if !copy.Solution().Equal(line.Solution()) {
+			t.Errorf("%s: solution = %v, got %v", format, line.Solution().Assignments, copy.Solution().Assignments)
+		}
+
+		if copy.Task(1).Name != "Weld" || copy.Task(3).Pred(1) == nil {
+			t.Errorf("%s: task 1 = Weld with successor 3, got %q %v", format, copy.Task(1).Name, copy.Task(3).Preds())
+		}
+
+		if reasons := copy.ExplainAssignment(1, 2); len(reasons) != 1 {
+			t.Errorf("%s: copy.ExplainAssignment(1, 2) = 1 reason, got %v", format, reasons)
+		}
+	}
+}
+
+type testConstraint struct{}
+
+func (c *testConstraint) Valid(task *Task, station *Station) bool {
+	return true
+}
+
+func TestDefinitionErrors(t *testing.T) {
+	inputs := []struct {
+		name  string
+		input string
+	}{
+		{"unknown constraint", `{"tasks": [{"id": 1, "time": 1}], "constraints": [{"type": "Unknown"}]}`},
+		{"missing time", `{"tasks": [{"id": 1, "time": 1}], "constraints": [{"type": "PacedLine"}]}`},
+		{"unknown predecessor", `{"tasks": [{"id": 1, "time": 1, "preds": [2]}]}`},
+		{"self-loop", `{"tasks": [{"id": 1, "time": 1, "preds": [1]}]}`},
+		{"cycle", `{"tasks": [{"id": 1, "time": 1, "preds": [2]}, {"id": 2, "time": 1, "preds": [1]}]}`},
+		{"unknown task", `{"tasks": [{"id": 1, "time": 1}], "stations": [{"id": 1, "tasks": [2]}]}`},
+		{"double assignment", `{"tasks": [{"id": 1, "time": 1}], "stations": [{"id": 1, "tasks": [1]}, {"id": 2, "tasks": [1]}]}`},
+		{"unknown direction", `{"direction": "sideways", "tasks": [{"id": 1, "time": 1}]}`},
+		{"overload", `{"tasks": [{"id": 1, "time": 6}, {"id": 2, "time": 6}], "stations": [{"id": 1, "tasks": [1, 2]}],
+			"constraints": [{"type": "RestrictedStationTime", "params": {"time": 10}}]}`},
+		{"precedence", `{"tasks": [{"id": 1, "time": 1}, {"id": 2, "time": 1, "preds": [1]}],
+			"stations": [{"id": 1, "tasks": [2]}, {"id": 2, "tasks": [1]}]}`},
+		{"unassigned predecessor", `{"tasks": [{"id": 1, "time": 1}, {"id": 2, "time": 1, "preds": [1]}],
+			"stations": [{"id": 1, "tasks": [2]}]}`},
+	}
+
+	for _, in := range inputs {
+		def, err := ReadDefinitionJSON(strings.NewReader(in.input))
+		if err != nil {
+			t.Fatalf("%s: ReadDefinitionJSON returned an error, %s", in.name, err)
+		}
+
+		if _, err := def.Line(); err == nil {
+			t.Errorf("%s: def.Line() = error, got nil", in.name)
+		}
+	}
+
+	if _, err := ReadDefinitionJSON(strings.NewReader(`{"taks": []}`)); err == nil {
+		t.Errorf("ReadDefinitionJSON(unknown field) = error, got nil")
+	}
+
+	line := newTestLine("TestDefinitionErrors", []float64{1}, nil, 10)
+	line.Task(1).AddPred(NewTask(2, 1))
+	if _, err := line.Definition(); err == nil {
+		t.Errorf("line.Definition() with a predecessor off the line = error, got nil")
+	}
+
+	line = newTestLine("TestDefinitionErrors", []float64{1}, nil, 10)
+	line.AddConstraint(&testConstraint{})
+	if _, err := line.Definition(); err == nil {
+		t.Errorf("line.Definition() with a custom constraint = error, got nil")
+	}
+
+	ConstraintTypes["testConstraint"] = func(map[string]float64) (Constraint, error) {
+		return &testConstraint{}, nil
+	}
+	defer delete(ConstraintTypes, "testConstraint")
+
+	if _, err := line.Definition(); err != nil {
+		t.Errorf("line.Definition() with a registered constraint returned an error, %s", err)
+	}
+}
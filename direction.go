@@ -0,0 +1,203 @@
+package alb
+
+import "fmt"
+
+// Direction is the direction a line's stations are filled in when it is
+// balanced.
+type Direction int
+
+const (
+	// Forward fills stations from the start of the line, assigning tasks
+	// once their predecessors are assigned.
+	Forward Direction = iota
+
+	// Reverse fills stations from the end of the line on the reversed
+	// precedence graph, assigning tasks once their successors are assigned.
+	Reverse
+
+	// Bidirectional fills stations from both ends of the line, loading
+	// whichever end's next station takes the most work each time.
+	Bidirectional
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Forward:
+		return "forward"
+	case Reverse:
+		return "reverse"
+	case Bidirectional:
+		return "bidirectional"
+	}
+	return fmt.Sprintf("Direction(%d)", int(d))
+}
+
+// ParseDirection returns the direction with the given name, as returned by
+// String.
+func ParseDirection(name string) (Direction, error) {
+	for _, d := range []Direction{Forward, Reverse, Bidirectional} {
+		if d.String() == name {
+			return d, nil
+		}
+	}
+	return Forward, fmt.Errorf("unknown direction %q", name)
+}
+
+// mirror is a reversed clone of a line: every task's successors are its
+// predecessors, and the stations' ids are reversed so the last station comes
+// first. Balancing the clone forward balances the line in reverse, with the
+// line's constraints applying as they would forward. The line, its tasks and
+// its stations are not changed until the clone's balance is applied to it.
+type mirror struct {
+	line *Line
+
+	// toLine maps the clone's station ids to the line's, and fromLine the
+	// line's to the clone's.
+	toLine   map[int]int
+	fromLine map[int]int
+}
+
+// mirror returns a reversed clone of the line, with its current assignment.
+func (l *Line) mirror() (*mirror, error) {
+	c, err := l.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range c.tasks {
+		task.predecessors, task.successors = task.successors, task.predecessors
+	}
+
+	m := &mirror{
+		line:     c,
+		toLine:   make(map[int]int),
+		fromLine: make(map[int]int),
+	}
+
+	stations := c.Stations()
+	c.stations = make(map[int]*Station)
+	for i, station := range stations {
+		id := stations[len(stations)-1-i].ID
+		m.toLine[id] = station.ID
+		m.fromLine[station.ID] = id
+	}
+
+	for _, station := range stations {
+		station.ID = m.fromLine[station.ID]
+		c.stations[station.ID] = station
+	}
+	return m, nil
+}
+
+// station returns the clone's station for the line's station id.
+func (m *mirror) station(id int) *Station {
+	return m.line.Station(m.fromLine[id])
+}
+
+// sync assigns the clone's tasks as the line's are assigned.
+func (m *mirror) sync(l *Line) error {
+	return m.line.Restore(remap(l.Snapshot(), m.fromLine))
+}
+
+// apply assigns the line's tasks as the clone's are assigned.
+func (m *mirror) apply(l *Line) error {
+	return l.Restore(remap(m.line.Snapshot(), m.toLine))
+}
+
+// remap returns the snapshot with its station ids mapped by ids.
+func remap(s *Snapshot, ids map[int]int) *Snapshot {
+	mapped := &Snapshot{tasks: make(map[int][]int), active: make(map[int]bool)}
+	for id, tasks := range s.tasks {
+		mapped.tasks[ids[id]] = tasks
+	}
+	for id := range s.active {
+		mapped.active[ids[id]] = true
+	}
+	return mapped
+}
+
+// reverse balances the line in reverse with balance, on a mirror of the
+// line, and applies the mirror's balance to the line.
+func (l *Line) reverse(balance func(*Line) error) error {
+	m, err := l.mirror()
+	if err != nil {
+		return err
+	}
+
+	err = balance(m.line)
+	if err != nil {
+		return err
+	}
+	return m.apply(l)
+}
+
+// fillBothEnds fills the line's stations from both ends. Each step it loads
+// the first unfilled station forward and the last unfilled station in
+// reverse, and keeps whichever load has the longer station time.
+func (l *Line) fillBothEnds(pick func(*Station, []*Task) *Task) error {
+	m, err := l.mirror()
+	if err != nil {
+		return err
+	}
+
+	stations := l.Stations()
+	front, back := 0, len(stations)-1
+	for front <= back && l.NFreeTasks() > 0 {
+		forward, err := l.tryLoad(stations[front], pick)
+		if err != nil {
+			return err
+		}
+
+		err = m.sync(l)
+		if err != nil {
+			return err
+		}
+
+		backward, err := m.line.tryLoad(m.station(stations[back].ID), pick)
+		if err != nil {
+			return err
+		}
+
+		station, load := stations[front], forward
+		if loadTime(backward) > loadTime(forward) {
+			station, load = stations[back], backward
+			back--
+		} else {
+			front++
+		}
+
+		for _, task := range load {
+			err := station.AssignTask(l.Task(task.ID))
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(load) > 0 {
+			station.Activate()
+		}
+	}
+
+	return nil
+}
+
+// tryLoad fills the station and returns the tasks it was loaded with,
+// leaving the station empty and disabled again.
+func (l *Line) tryLoad(station *Station, pick func(*Station, []*Task) *Task) ([]*Task, error) {
+	err := l.fillStation(station, pick)
+	if err != nil {
+		return nil, err
+	}
+
+	load := append([]*Task(nil), station.Tasks()...)
+	station.Disable()
+	return load, station.WithdrawTasks()
+}
+
+func loadTime(tasks []*Task) float64 {
+	var total float64
+	for _, task := range tasks {
+		total += task.Time()
+	}
+	return total
+}
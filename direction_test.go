@@ -0,0 +1,109 @@
+package alb
+
+import "testing"
+
+func TestBalanceDirection(t *testing.T) {
+	// Tasks 1 and 2 precede task 3, which is too long to share a station.
+	preds := [][2]int{{1, 3}, {2, 3}}
+
+	var tests = []struct {
+		direction Direction
+		want      map[int]int
+	}{
+		{Forward, map[int]int{1: 1, 2: 1, 3: 2}},
+		{Reverse, map[int]int{1: 2, 2: 2, 3: 3}},
+		{Bidirectional, map[int]int{1: 1, 2: 1, 3: 3}},
+	}
+
+	for _, test := range tests {
+		line := newTestLine("TestBalanceDirection", []float64{2, 2, 9}, preds, 10)
+		line.AddConstraint(&PredecessorsInPriorStations{})
+		line.Direction = test.direction
+
+		err := line.BalanceByStationId(LongestTaskTime)
+		if err != nil {
+			t.Fatalf("BalanceByStationId(%s) returned an error, %s", test.direction, err)
+		}
+
+		for taskID, stationID := range test.want {
+			station := line.Task(taskID).Assignment()
+			if station == nil || station.ID != stationID {
+				t.Errorf("BalanceByStationId(%s) task %d = station %d, got %v", test.direction, taskID, stationID, station)
+			}
+		}
+
+		if got := line.NActiveStations(); got != 2 {
+			t.Errorf("BalanceByStationId(%s).NActiveStations() = 2, got %d", test.direction, got)
+		}
+
+		// The line is restored after balancing in reverse.
+		if got := line.Task(3).Preds(); len(got) != 2 {
+			t.Errorf("task 3 preds after %s = 2, got %d", test.direction, len(got))
+		}
+		for i, station := range line.Stations() {
+			if station.ID != i+1 {
+				t.Errorf("station %d after %s = %d, got %d", i, test.direction, i+1, station.ID)
+			}
+		}
+	}
+}
+
+func TestShortestStationTimeReverse(t *testing.T) {
+	line := newTestLine("TestShortestStationTimeReverse", []float64{6, 6}, [][2]int{{1, 2}}, 10)
+	line.Direction = Reverse
+
+	err := line.BalanceByShortestStationTime(LongestTaskTime)
+	if err != nil {
+		t.Fatalf("BalanceByShortestStationTime(reverse) returned an error, %s", err)
+	}
+
+	if got := line.Task(2).Assignment().ID; got != 2 {
+		t.Errorf("BalanceByShortestStationTime(reverse) task 2 = station 2, got %d", got)
+	}
+
+	line.Direction = Bidirectional
+	if err := line.BalanceByShortestStationTime(LongestTaskTime); err == nil {
+		t.Error("BalanceByShortestStationTime(bidirectional) = error, got nil")
+	}
+}
+
+func TestReverseLeavesLineUnchanged(t *testing.T) {
+	preds := [][2]int{{1, 3}, {2, 3}}
+	line := newTestLine("TestReverseLeavesLineUnchanged", []float64{2, 2, 9}, preds, 10)
+	line.Direction = Reverse
+	first := line.Station(1)
+
+	// The heuristic sees the line as the caller does while it is balanced.
+	var calls int
+	observe := func(tasks []*Task) *Task {
+		calls++
+		if line.Station(1) != first || first.ID != 1 || len(line.Task(3).Preds()) != 2 {
+			t.Errorf("line changed while balancing in reverse")
+		}
+		return LongestTaskTime(tasks)
+	}
+
+	err := line.BalanceByStationId(observe)
+	if err != nil {
+		t.Fatalf("BalanceByStationId(reverse) returned an error, %s", err)
+	}
+
+	if calls == 0 || line.Task(3).Assignment() != line.Station(3) {
+		t.Errorf("task 3 = station 3, got %v", line.Task(3).Assignment())
+	}
+}
+
+func TestUnsupportedDirection(t *testing.T) {
+	line := newTestLine("TestUnsupportedDirection", []float64{2, 2, 9}, nil, 10)
+	line.Direction = Reverse
+
+	if _, err := line.BalanceByBranchAndBound(LongestTaskTime, BranchAndBoundLimits{}); err == nil {
+		t.Errorf("BalanceByBranchAndBound(reverse) = error, got nil")
+	}
+	if _, err := line.BalanceByBeamSearch(BeamOptions{}); err == nil {
+		t.Errorf("BalanceByBeamSearch(reverse) = error, got nil")
+	}
+	if _, err := line.BalanceByAntColony(AntColonyOptions{}); err == nil {
+		t.Errorf("BalanceByAntColony(reverse) = error, got nil")
+	}
+}
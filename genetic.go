@@ -0,0 +1,273 @@
+package alb
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// GeneticOptions configures BalanceByGeneticAlgorithm. Zero values select
+// the defaults given for each field. Since zero selects a default, a rate of
+// 0 or no elitism is configured with a negative value.
+type GeneticOptions struct {
+	// Objective is the cost being minimized (default MinimizeStations).
+	Objective Objective
+
+	// PopulationSize is the number of individuals (default 50).
+	PopulationSize int
+
+	// CrossoverRate is the chance two parents are recombined rather than
+	// the first being copied (default 0.9, negative for none).
+	CrossoverRate float64
+
+	// MutationRate is the chance each task's priority is redrawn in a new
+	// individual (default 0.05, negative for none).
+	MutationRate float64
+
+	// Elitism is the number of best individuals carried unchanged into the
+	// next generation (default 2, negative for none).
+	Elitism int
+
+	// Generations is the number of generations bred (default 100).
+	Generations int
+
+	// TimeLimit stops the search early, if non-zero.
+	TimeLimit time.Duration
+
+	// Seed seeds the random choices, so runs with the same seed and line
+	// produce the same balance.
+	Seed int64
+}
+
+// GeneticResult describes the outcome of BalanceByGeneticAlgorithm.
+type GeneticResult struct {
+	Stations   int
+	FreeTasks  int
+	Smoothness float64
+	Cost       float64
+
+	// Generations is the number of generations bred, and BestGeneration the
+	// one the best individual was found in (0 for the initial population).
+	Generations    int
+	BestGeneration int
+
+	// Solution is the balance assigned to the line.
+	Solution *Solution
+
+	Elapsed time.Duration
+}
+
+// BalanceByGeneticAlgorithm balances the line with a genetic algorithm.
+// Each individual is a priority for every task, decoded into a balance by
+// BalanceByStationId using the priorities as its heuristic, so every
+// individual decodes to a balance that satisfies the line's constraints.
+// The initial population includes the RankedPositionalWeight priorities.
+//
+// Individuals are ranked by free tasks and then by the objective's cost, and
+// the best individual's balance is assigned to the line when it finishes.
+func (l *Line) BalanceByGeneticAlgorithm(opts GeneticOptions) (*GeneticResult, error) {
+	start := time.Now()
+	opts = opts.withDefaults()
+
+	g := &genetic{
+		line:  l,
+		opts:  opts,
+		rng:   rand.New(rand.NewSource(opts.Seed)),
+		tasks: l.Tasks(),
+	}
+
+	population := make([]*individual, 0, opts.PopulationSize)
+	population = append(population, &individual{keys: normalize(RankedPositionalWeight(l))})
+	for len(population) < opts.PopulationSize {
+		population = append(population, g.random())
+	}
+
+	err := g.evaluate(population)
+	if err != nil {
+		return nil, err
+	}
+
+	best := population[0]
+	result := &GeneticResult{}
+	for result.Generations < opts.Generations {
+		if opts.TimeLimit > 0 && time.Since(start) >= opts.TimeLimit {
+			break
+		}
+
+		next := append([]*individual(nil), population[:opts.Elitism]...)
+		for len(next) < opts.PopulationSize {
+			child := g.crossover(g.tournament(population), g.tournament(population))
+			g.mutate(child)
+			next = append(next, child)
+		}
+
+		err := g.evaluate(next[opts.Elitism:])
+		if err != nil {
+			return nil, err
+		}
+
+		population = next
+		g.sort(population)
+		result.Generations++
+		if population[0].better(best) {
+			best = population[0]
+			result.BestGeneration = result.Generations
+		}
+	}
+
+	err = l.Restore(best.assignment)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Stations = l.NActiveStations()
+	result.FreeTasks = best.free
+	result.Smoothness = l.smoothness()
+	result.Cost = best.cost
+	result.Solution = l.solution("genetic")
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
+
+// withDefaults returns the options with the defaults filled in.
+func (opts GeneticOptions) withDefaults() GeneticOptions {
+	if opts.Objective == (Objective{}) {
+		opts.Objective = MinimizeStations
+	}
+	if opts.PopulationSize <= 0 {
+		opts.PopulationSize = 50
+	}
+	switch {
+	case opts.CrossoverRate == 0:
+		opts.CrossoverRate = 0.9
+	case opts.CrossoverRate < 0:
+		opts.CrossoverRate = 0
+	}
+	switch {
+	case opts.MutationRate == 0:
+		opts.MutationRate = 0.05
+	case opts.MutationRate < 0:
+		opts.MutationRate = 0
+	}
+	switch {
+	case opts.Elitism == 0:
+		opts.Elitism = 2
+	case opts.Elitism < 0:
+		opts.Elitism = 0
+	}
+	if opts.Elitism > opts.PopulationSize {
+		opts.Elitism = opts.PopulationSize
+	}
+	if opts.Generations <= 0 {
+		opts.Generations = 100
+	}
+	return opts
+}
+
+// individual is a task priority vector and the balance it decodes to.
+type individual struct {
+	keys Priorities
+	costedBalance
+}
+
+// better reports whether i decodes to a better balance than o.
+func (i *individual) better(o *individual) bool {
+	return i.costedBalance.better(&o.costedBalance)
+}
+
+type genetic struct {
+	line  *Line
+	opts  GeneticOptions
+	rng   *rand.Rand
+	tasks []*Task
+}
+
+// random returns an individual with uniformly random priorities.
+func (g *genetic) random() *individual {
+	keys := make(Priorities)
+	for _, task := range g.tasks {
+		keys[task.ID] = g.rng.Float64()
+	}
+	return &individual{keys: keys}
+}
+
+// evaluate decodes each individual into a balance of the line and sorts the
+// individuals from best to worst.
+func (g *genetic) evaluate(population []*individual) error {
+	for _, ind := range population {
+		err := g.line.reset()
+		if err != nil {
+			return err
+		}
+
+		err = g.line.BalanceByStationId(ind.keys.Heuristic())
+		if err != nil {
+			return err
+		}
+
+		ind.assignment = g.line.Snapshot()
+		ind.free = g.line.NFreeTasks()
+		ind.cost = g.opts.Objective.Cost(g.line)
+	}
+
+	g.sort(population)
+	return nil
+}
+
+func (g *genetic) sort(population []*individual) {
+	sort.SliceStable(population, func(i, j int) bool {
+		return population[i].better(population[j])
+	})
+}
+
+// tournament returns the better of two random individuals.
+func (g *genetic) tournament(population []*individual) *individual {
+	a := population[g.rng.Intn(len(population))]
+	b := population[g.rng.Intn(len(population))]
+	if b.better(a) {
+		return b
+	}
+	return a
+}
+
+// crossover returns a child taking each task's priority from either parent
+// at random, or a copy of the first parent.
+func (g *genetic) crossover(a, b *individual) *individual {
+	recombine := g.rng.Float64() < g.opts.CrossoverRate
+	keys := make(Priorities)
+	for _, task := range g.tasks {
+		keys[task.ID] = a.keys[task.ID]
+		if recombine && g.rng.Intn(2) == 0 {
+			keys[task.ID] = b.keys[task.ID]
+		}
+	}
+	return &individual{keys: keys}
+}
+
+// mutate redraws each of the individual's priorities with the mutation rate.
+func (g *genetic) mutate(ind *individual) {
+	for _, task := range g.tasks {
+		if g.rng.Float64() < g.opts.MutationRate {
+			ind.keys[task.ID] = g.rng.Float64()
+		}
+	}
+}
+
+// normalize scales priorities into [0, 1], keeping their order.
+func normalize(p Priorities) Priorities {
+	var max float64
+	for _, v := range p {
+		if v > max {
+			max = v
+		}
+	}
+
+	n := make(Priorities)
+	for id, v := range p {
+		n[id] = v
+		if max > 0 {
+			n[id] = v / max
+		}
+	}
+	return n
+}
@@ -0,0 +1,81 @@
+package alb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGeneticAlgorithm(t *testing.T) {
+	line := newTestLine("TestGeneticAlgorithm", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+
+	result, err := line.BalanceByGeneticAlgorithm(GeneticOptions{PopulationSize: 20, Generations: 20, Seed: 1})
+	if err != nil {
+		t.Fatalf("BalanceByGeneticAlgorithm returned an error, %s", err)
+	}
+
+	if result.Stations != 2 || result.FreeTasks != 0 {
+		t.Errorf("BalanceByGeneticAlgorithm() = 2 stations 0 free, got %d stations %d free", result.Stations, result.FreeTasks)
+	}
+
+	if got := line.NActiveStations(); got != result.Stations {
+		t.Errorf("line.NActiveStations() = %d, got %d", result.Stations, got)
+	}
+
+	if result.Generations != 20 {
+		t.Errorf("BalanceByGeneticAlgorithm() = 20 generations, got %d", result.Generations)
+	}
+}
+
+func TestGeneticOptionsDefaults(t *testing.T) {
+	var tests = []struct {
+		opts                    GeneticOptions
+		crossover, mutation     float64
+		elitism, populationSize int
+	}{
+		{GeneticOptions{}, 0.9, 0.05, 2, 50},
+		{GeneticOptions{CrossoverRate: -1, MutationRate: -1, Elitism: -1}, 0, 0, 0, 50},
+		{GeneticOptions{CrossoverRate: 0.5, MutationRate: 0.1, Elitism: 20, PopulationSize: 10}, 0.5, 0.1, 10, 10},
+	}
+
+	for _, test := range tests {
+		got := test.opts.withDefaults()
+		if got.CrossoverRate != test.crossover || got.MutationRate != test.mutation ||
+			got.Elitism != test.elitism || got.PopulationSize != test.populationSize {
+			t.Errorf("%+v.withDefaults() = %v %v %d %d, got %v %v %d %d", test.opts,
+				test.crossover, test.mutation, test.elitism, test.populationSize,
+				got.CrossoverRate, got.MutationRate, got.Elitism, got.PopulationSize)
+		}
+	}
+
+	// Without elitism the whole population is bred each generation.
+	line := newTestLine("TestGeneticOptionsDefaults", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+	opts := GeneticOptions{CrossoverRate: -1, MutationRate: -1, Elitism: -1, PopulationSize: 5, Generations: 5, Seed: 1}
+	result, err := line.BalanceByGeneticAlgorithm(opts)
+	if err != nil {
+		t.Fatalf("BalanceByGeneticAlgorithm returned an error, %s", err)
+	}
+
+	if result.Generations != 5 || result.FreeTasks != 0 {
+		t.Errorf("BalanceByGeneticAlgorithm() = 5 generations 0 free, got %d %d", result.Generations, result.FreeTasks)
+	}
+}
+
+func TestGeneticAlgorithmSeed(t *testing.T) {
+	preds := [][2]int{{1, 3}, {2, 4}, {3, 5}}
+	times := []float64{5, 4, 3, 3, 3, 2, 6, 1}
+
+	var assignments []*Snapshot
+	for i := 0; i < 2; i++ {
+		line := newTestLine("TestGeneticAlgorithmSeed", times, preds, 10)
+		opts := GeneticOptions{Objective: MinimizeSmoothness, PopulationSize: 10, Generations: 5, Seed: 9}
+		_, err := line.BalanceByGeneticAlgorithm(opts)
+		if err != nil {
+			t.Fatalf("BalanceByGeneticAlgorithm returned an error, %s", err)
+		}
+		assignments = append(assignments, line.Snapshot())
+	}
+
+	if !reflect.DeepEqual(assignments[0], assignments[1]) {
+		t.Errorf("BalanceByGeneticAlgorithm(Seed: 9) = %v, got %v", assignments[0], assignments[1])
+	}
+}
@@ -0,0 +1,282 @@
+package alb
+
+import (
+	"math"
+	"sort"
+)
+
+// PrecedenceGraph is the precedence graph of a line's tasks, built from
+// Task.Preds(), with the indicators used to characterize SALBP instances.
+// It is a snapshot: changing the line's tasks or predecessors afterwards
+// does not change the graph.
+type PrecedenceGraph struct {
+	tasks []*Task
+	preds map[int][]*Task
+	succs map[int][]*Task
+
+	// allPreds and allSuccs are the transitive closure, sorted by id.
+	allPreds map[int][]*Task
+	allSuccs map[int][]*Task
+}
+
+// PrecedenceGraph returns the line's precedence graph. It returns the error
+// from ValidatePrecedence if the graph has a cycle or refers to tasks that
+// are not on the line.
+func (l *Line) PrecedenceGraph() (*PrecedenceGraph, error) {
+	err := l.ValidatePrecedence()
+	if err != nil {
+		return nil, err
+	}
+	return newPrecedenceGraph(l.Tasks()), nil
+}
+
+// newPrecedenceGraph builds the precedence graph of the tasks, ignoring
+// predecessors that are not among them. Unlike Line.PrecedenceGraph it
+// accepts cycles, so only the closure is meaningful for a cyclic graph.
+func newPrecedenceGraph(tasks []*Task) *PrecedenceGraph {
+	g := &PrecedenceGraph{
+		tasks: tasks,
+		preds: make(map[int][]*Task),
+		succs: make(map[int][]*Task),
+	}
+
+	known := make(map[int]*Task)
+	for _, task := range tasks {
+		known[task.ID] = task
+	}
+
+	for _, task := range tasks {
+		for _, pred := range task.Preds() {
+			if known[pred.ID] != pred {
+				continue
+			}
+			g.preds[task.ID] = append(g.preds[task.ID], pred)
+			g.succs[pred.ID] = append(g.succs[pred.ID], task)
+		}
+	}
+
+	g.allPreds = closure(tasks, g.preds)
+	g.allSuccs = closure(tasks, g.succs)
+	return g
+}
+
+// closure returns the tasks reachable from each task over next, sorted by id.
+func closure(tasks []*Task, next map[int][]*Task) map[int][]*Task {
+	reach := make(map[int][]*Task)
+	for _, task := range tasks {
+		seen := map[int]bool{task.ID: true}
+		stack := append([]*Task(nil), next[task.ID]...)
+		var found []*Task
+		for len(stack) > 0 {
+			t := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[t.ID] {
+				continue
+			}
+
+			seen[t.ID] = true
+			found = append(found, t)
+			stack = append(stack, next[t.ID]...)
+		}
+
+		sort.Slice(found, func(i, j int) bool { return found[i].ID < found[j].ID })
+		reach[task.ID] = found
+	}
+	return reach
+}
+
+// Tasks returns the graph's tasks, sorted by id.
+func (g *PrecedenceGraph) Tasks() []*Task {
+	return g.tasks
+}
+
+// Preds returns the direct predecessors of a task, sorted by id.
+func (g *PrecedenceGraph) Preds(id int) []*Task {
+	return g.preds[id]
+}
+
+// Succs returns the direct successors of a task, sorted by id.
+func (g *PrecedenceGraph) Succs(id int) []*Task {
+	return g.succs[id]
+}
+
+// AllPreds returns the direct and indirect predecessors of a task, sorted by
+// id.
+func (g *PrecedenceGraph) AllPreds(id int) []*Task {
+	return g.allPreds[id]
+}
+
+// AllSuccs returns the direct and indirect successors of a task, sorted by
+// id.
+func (g *PrecedenceGraph) AllSuccs(id int) []*Task {
+	return g.allSuccs[id]
+}
+
+// Arcs returns the graph's direct precedence relations as [predecessor,
+// task] id pairs, sorted.
+func (g *PrecedenceGraph) Arcs() [][2]int {
+	return g.arcs(g.preds)
+}
+
+// Closure returns the arcs of the transitive closure: a [predecessor, task]
+// pair for every task and each of its direct and indirect predecessors,
+// sorted.
+func (g *PrecedenceGraph) Closure() [][2]int {
+	return g.arcs(g.allPreds)
+}
+
+// Reduction returns the arcs of the transitive reduction: the direct
+// precedence relations that are not implied by the others, sorted.
+func (g *PrecedenceGraph) Reduction() [][2]int {
+	var arcs [][2]int
+	for _, arc := range g.Arcs() {
+		if !g.redundant(arc) {
+			arcs = append(arcs, arc)
+		}
+	}
+	return arcs
+}
+
+// RedundantArcs returns the direct precedence relations that are implied by
+// the others, i.e. the arcs removed by the transitive reduction, sorted.
+func (g *PrecedenceGraph) RedundantArcs() [][2]int {
+	var arcs [][2]int
+	for _, arc := range g.Arcs() {
+		if g.redundant(arc) {
+			arcs = append(arcs, arc)
+		}
+	}
+	return arcs
+}
+
+// redundant reports whether the arc's predecessor also precedes another of
+// the task's direct predecessors.
+func (g *PrecedenceGraph) redundant(arc [2]int) bool {
+	for _, pred := range g.preds[arc[1]] {
+		if pred.ID == arc[0] {
+			continue
+		}
+
+		for _, p := range g.allPreds[pred.ID] {
+			if p.ID == arc[0] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (g *PrecedenceGraph) arcs(preds map[int][]*Task) [][2]int {
+	var arcs [][2]int
+	for _, task := range g.tasks {
+		for _, pred := range preds[task.ID] {
+			arcs = append(arcs, [2]int{pred.ID, task.ID})
+		}
+	}
+
+	sort.Slice(arcs, func(i, j int) bool {
+		if arcs[i][0] != arcs[j][0] {
+			return arcs[i][0] < arcs[j][0]
+		}
+		return arcs[i][1] < arcs[j][1]
+	})
+	return arcs
+}
+
+// TopologicalOrder returns the tasks in an order where every task comes
+// after its predecessors, choosing the lowest id among the tasks available
+// at each step.
+func (g *PrecedenceGraph) TopologicalOrder() []*Task {
+	remaining := make(map[int]int)
+	var ready []*Task
+	for _, task := range g.tasks {
+		remaining[task.ID] = len(g.preds[task.ID])
+		if remaining[task.ID] == 0 {
+			ready = append(ready, task)
+		}
+	}
+
+	var order []*Task
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return ready[i].ID < ready[j].ID })
+		task := ready[0]
+		ready = ready[1:]
+		order = append(order, task)
+
+		for _, succ := range g.succs[task.ID] {
+			remaining[succ.ID]--
+			if remaining[succ.ID] == 0 {
+				ready = append(ready, succ)
+			}
+		}
+	}
+	return order
+}
+
+// Levels returns the depth of each task, keyed by task id: 1 for tasks
+// without predecessors, and otherwise one more than the deepest of its
+// predecessors.
+func (g *PrecedenceGraph) Levels() map[int]int {
+	levels := make(map[int]int)
+	for _, task := range g.TopologicalOrder() {
+		levels[task.ID] = 1
+		for _, pred := range g.preds[task.ID] {
+			if levels[pred.ID]+1 > levels[task.ID] {
+				levels[task.ID] = levels[pred.ID] + 1
+			}
+		}
+	}
+	return levels
+}
+
+// Depth returns the number of levels of the graph, i.e. the number of tasks
+// on its longest chain.
+func (g *PrecedenceGraph) Depth() int {
+	var depth int
+	for _, level := range g.Levels() {
+		if level > depth {
+			depth = level
+		}
+	}
+	return depth
+}
+
+// OrderStrength returns the number of arcs of the transitive closure divided
+// by the number of possible arcs, n(n-1)/2 for n tasks. It ranges from 0,
+// for independent tasks, to 1, for a single chain.
+func (g *PrecedenceGraph) OrderStrength() float64 {
+	n := len(g.tasks)
+	if n < 2 {
+		return 0
+	}
+	return float64(len(g.Closure())) / (float64(n*(n-1)) / 2)
+}
+
+// WestRatio returns the average number of tasks per level, i.e. the number
+// of tasks divided by the depth of the graph.
+func (g *PrecedenceGraph) WestRatio() float64 {
+	depth := g.Depth()
+	if depth == 0 {
+		return 0
+	}
+	return float64(len(g.tasks)) / float64(depth)
+}
+
+// TimeVariability returns the ratio of the longest to the shortest task
+// time, or 0 for a graph without tasks or with a task that takes no time.
+func (g *PrecedenceGraph) TimeVariability() float64 {
+	if len(g.tasks) == 0 {
+		return 0
+	}
+
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, task := range g.tasks {
+		min = math.Min(min, task.Time())
+		max = math.Max(max, task.Time())
+	}
+
+	if min <= 0 {
+		return 0
+	}
+	return max / min
+}
@@ -0,0 +1,97 @@
+package alb
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestPrecedenceGraph(t *testing.T) {
+	// 1 -> 3 is implied by 1 -> 2 -> 3.
+	preds := [][2]int{{1, 2}, {2, 3}, {1, 3}, {1, 4}}
+	line := newTestLine("TestPrecedenceGraph", []float64{2, 4, 6, 8, 1}, preds, 10)
+
+	g, err := line.PrecedenceGraph()
+	if err != nil {
+		t.Fatalf("line.PrecedenceGraph() returned an error, %s", err)
+	}
+
+	arcs := []struct {
+		name string
+		got  [][2]int
+		want [][2]int
+	}{
+		{"Arcs", g.Arcs(), [][2]int{{1, 2}, {1, 3}, {1, 4}, {2, 3}}},
+		{"Closure", g.Closure(), [][2]int{{1, 2}, {1, 3}, {1, 4}, {2, 3}}},
+		{"Reduction", g.Reduction(), [][2]int{{1, 2}, {1, 4}, {2, 3}}},
+		{"RedundantArcs", g.RedundantArcs(), [][2]int{{1, 3}}},
+	}
+
+	for _, test := range arcs {
+		if !reflect.DeepEqual(test.got, test.want) {
+			t.Errorf("g.%s() = %v, got %v", test.name, test.want, test.got)
+		}
+	}
+
+	var order []int
+	for _, task := range g.TopologicalOrder() {
+		order = append(order, task.ID)
+	}
+	if want := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(order, want) {
+		t.Errorf("g.TopologicalOrder() = %v, got %v", want, order)
+	}
+
+	if want := map[int]int{1: 1, 2: 2, 3: 3, 4: 2, 5: 1}; !reflect.DeepEqual(g.Levels(), want) {
+		t.Errorf("g.Levels() = %v, got %v", want, g.Levels())
+	}
+
+	indicators := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"Depth", float64(g.Depth()), 3},
+		{"OrderStrength", g.OrderStrength(), 0.4},
+		{"WestRatio", g.WestRatio(), 5.0 / 3},
+		{"TimeVariability", g.TimeVariability(), 8},
+	}
+
+	for _, test := range indicators {
+		if math.Abs(test.got-test.want) > eps {
+			t.Errorf("g.%s() = %.3f, got %.3f", test.name, test.want, test.got)
+		}
+	}
+}
+
+func TestTimeVariabilityZeroTime(t *testing.T) {
+	tests := []struct {
+		times []float64
+		want  float64
+	}{
+		{[]float64{2, 4}, 2},
+		{[]float64{0, 4}, 0},
+		{[]float64{0, 0}, 0},
+		{nil, 0},
+	}
+
+	for _, test := range tests {
+		line := newTestLine("TestTimeVariabilityZeroTime", test.times, nil, 10)
+		g, err := line.PrecedenceGraph()
+		if err != nil {
+			t.Fatalf("line.PrecedenceGraph() returned an error, %s", err)
+		}
+
+		if got := g.TimeVariability(); got != test.want {
+			t.Errorf("g.TimeVariability() with times %v = %.2f, got %.2f", test.times, test.want, got)
+		}
+	}
+}
+
+func TestPrecedenceGraphCycle(t *testing.T) {
+	line := newTestLine("TestPrecedenceGraphCycle", []float64{1, 1}, [][2]int{{1, 2}, {2, 1}}, 10)
+
+	_, err := line.PrecedenceGraph()
+	if _, ok := err.(*PrecedenceError); !ok {
+		t.Errorf("line.PrecedenceGraph() = *PrecedenceError, got %v", err)
+	}
+}
@@ -2,6 +2,17 @@ package alb
 
 type Heuristic func([]*Task) *Task
 
+// Heuristics are the heuristics known by name, e.g. to the balance command
+// and BalancePortfolio.
+var Heuristics = map[string]Heuristic{
+	"ShortestTaskTime":      ShortestTaskTime,
+	"LongestTaskTime":       LongestTaskTime,
+	"LongestSuccessorTime":  LongestSuccesssorTime,
+	"ShortestSuccessorTime": ShortestSuccessorTime,
+	"MostSuccessors":        MostSuccessors,
+	"LeastSuccessors":       LeastSuccessors,
+}
+
 func ShortestTaskTime(tasks []*Task) *Task {
 	var min *Task
 	for _, task := range tasks {
@@ -33,95 +44,98 @@ func LongestTaskTime(tasks []*Task) *Task {
 	return max
 }
 
-// NOTE: Experimental, not tested
+// TimeOfSuccessors returns the task's time plus the time of each of its
+// direct and indirect successors, counting each successor once. If tasks is
+// not nil, only successors reached through tasks in it are counted.
 func TimeOfSuccessors(task *Task, tasks []*Task) float64 {
 	total := task.Time()
-	for _, t := range tasks {
-		if t.Pred(task.ID) != nil {
-			total += TimeOfSuccessors(t, tasks)
-		}
+	for _, succ := range successors(task, tasks) {
+		total += succ.Time()
 	}
 	return total
 }
 
-// NOTE: Experimental, not tested
-func LongestSuccesssorTime(tasks []*Task) *Task {
-	var maxLength float64
-	var maxTask *Task
-	for _, task := range tasks {
-		if maxTask == nil {
-			maxTask = task
-			continue
-		}
+// NSuccessors returns one for the task plus the number of its direct and
+// indirect successors. If tasks is not nil, only successors reached through
+// tasks in it are counted.
+func NSuccessors(task *Task, tasks []*Task) int {
+	return 1 + len(successors(task, tasks))
+}
 
-		length := TimeOfSuccessors(task, tasks)
-		if length > maxLength {
-			maxTask = task
+// successors returns the task's direct and indirect successors, following
+// successor links through tasks in the given set, or through all tasks if
+// it is nil. It visits each successor and link once.
+func successors(task *Task, tasks []*Task) []*Task {
+	var in map[int]bool
+	if tasks != nil {
+		in = make(map[int]bool)
+		for _, t := range tasks {
+			in[t.ID] = true
 		}
 	}
-	return maxTask
-}
 
-// NOTE: Experimental, not tested
-func ShortestSuccessorTime(tasks []*Task) *Task {
-	var minLength float64
-	var minTask *Task
-	for _, task := range tasks {
-		if minTask == nil {
-			minTask = task
-			continue
-		}
+	seen := map[int]bool{task.ID: true}
+	stack := []*Task{task}
+	var found []*Task
+	for len(stack) > 0 {
+		t := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, succ := range t.Succs() {
+			if seen[succ.ID] || (in != nil && !in[succ.ID]) {
+				continue
+			}
 
-		length := TimeOfSuccessors(task, tasks)
-		if length < minLength {
-			minTask = task
+			seen[succ.ID] = true
+			found = append(found, succ)
+			stack = append(stack, succ)
 		}
 	}
-	return minTask
+	return found
 }
 
-func NSuccessors(task *Task, tasks []*Task) int {
-	total := 1
-	for _, t := range tasks {
-		if t.Pred(task.ID) != nil {
-			total += NSuccessors(t, tasks)
-		}
-	}
-	return total
+// LongestSuccesssorTime picks the task with the largest TimeOfSuccessors,
+// breaking ties by the lowest task id.
+func LongestSuccesssorTime(tasks []*Task) *Task {
+	return bySuccessors(tasks, func(task *Task) float64 {
+		return TimeOfSuccessors(task, nil)
+	})
 }
 
-// NOTE: Experimental, not tested
-func MostSuccessors(tasks []*Task) *Task {
-	var maxLength int
-	var maxTask *Task
-	for _, task := range tasks {
-		if maxTask == nil {
-			maxTask = task
-			continue
-		}
+// ShortestSuccessorTime picks the task with the smallest TimeOfSuccessors,
+// breaking ties by the lowest task id.
+func ShortestSuccessorTime(tasks []*Task) *Task {
+	return bySuccessors(tasks, func(task *Task) float64 {
+		return -TimeOfSuccessors(task, nil)
+	})
+}
 
-		length := NSuccessors(task, tasks)
-		if length > maxLength {
-			maxTask = task
-		}
-	}
-	return maxTask
+// MostSuccessors picks the task with the most direct and indirect
+// successors, breaking ties by the lowest task id.
+func MostSuccessors(tasks []*Task) *Task {
+	return bySuccessors(tasks, func(task *Task) float64 {
+		return float64(NSuccessors(task, nil))
+	})
 }
 
-// NOTE: Experimental, not tested
+// LeastSuccessors picks the task with the fewest direct and indirect
+// successors, breaking ties by the lowest task id.
 func LeastSuccessors(tasks []*Task) *Task {
-	var minLength int
-	var minTask *Task
-	for _, task := range tasks {
-		if minTask == nil {
-			minTask = task
-			continue
-		}
+	return bySuccessors(tasks, func(task *Task) float64 {
+		return -float64(NSuccessors(task, nil))
+	})
+}
 
-		length := NSuccessors(task, tasks)
-		if length < minLength {
-			minTask = task
+// bySuccessors picks the task with the highest value, breaking ties by the
+// lowest task id.
+func bySuccessors(tasks []*Task, value func(*Task) float64) *Task {
+	var best *Task
+	var bestValue float64
+	for _, task := range tasks {
+		v := value(task)
+		if best == nil || v > bestValue || (v == bestValue && task.ID < best.ID) {
+			best = task
+			bestValue = v
 		}
 	}
-	return minTask
+	return best
 }
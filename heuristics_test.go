@@ -0,0 +1,40 @@
+package alb
+
+import "testing"
+
+func TestSuccessorHeuristics(t *testing.T) {
+	// A diamond 1 -> {2, 3} -> 4, where 4 is counted once, and a lone task 5.
+	preds := [][2]int{{1, 2}, {1, 3}, {2, 4}, {3, 4}}
+	line := newTestLine("TestSuccessorHeuristics", []float64{1, 2, 3, 4, 20}, preds, 30)
+
+	if got := NSuccessors(line.Task(1), nil); got != 4 {
+		t.Errorf("NSuccessors(1, nil) = 4, got %d", got)
+	}
+
+	if got := TimeOfSuccessors(line.Task(1), nil); got != 10 {
+		t.Errorf("TimeOfSuccessors(1, nil) = 10, got %.2f", got)
+	}
+
+	within := []*Task{line.Task(1), line.Task(2), line.Task(4)}
+	if got := NSuccessors(line.Task(1), within); got != 3 {
+		t.Errorf("NSuccessors(1, [1 2 4]) = 3, got %d", got)
+	}
+
+	candidates := []*Task{line.Task(1), line.Task(5)}
+	var tests = []struct {
+		name string
+		fn   Heuristic
+		want int
+	}{
+		{"LongestSuccesssorTime", LongestSuccesssorTime, 5},
+		{"ShortestSuccessorTime", ShortestSuccessorTime, 1},
+		{"MostSuccessors", MostSuccessors, 1},
+		{"LeastSuccessors", LeastSuccessors, 5},
+	}
+
+	for _, test := range tests {
+		if got := test.fn(candidates); got.ID != test.want {
+			t.Errorf("%s([1 5]) = %d, got %d", test.name, test.want, got.ID)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package alb
+
+import "sort"
+
+// Objective weighs the cost of a balance: Stations times the number of
+// active stations plus Smoothness times the smoothness index.
+type Objective struct {
+	Stations   float64
+	Smoothness float64
+}
+
+var (
+	// MinimizeStations only counts the number of active stations.
+	MinimizeStations = Objective{Stations: 1}
+
+	// MinimizeSmoothness only counts the smoothness index.
+	MinimizeSmoothness = Objective{Smoothness: 1}
+)
+
+// Cost returns the cost of the line's current balance. The smoothness index
+// is measured at the line's cycle time, or at its longest station time if it
+// has no cycle time.
+func (o Objective) Cost(line *Line) float64 {
+	var cost float64
+	if o.Stations != 0 {
+		cost += o.Stations * float64(line.NActiveStations())
+	}
+	if o.Smoothness != 0 {
+		cost += o.Smoothness * line.smoothness()
+	}
+	return cost
+}
+
+// costedBalance is an assignment of the line saved with its free tasks and
+// cost under an objective.
+type costedBalance struct {
+	assignment *Snapshot
+	free       int
+	cost       float64
+}
+
+// better reports whether b leaves fewer tasks free than o, or as many at a
+// lower cost.
+func (b *costedBalance) better(o *costedBalance) bool {
+	if b.free != o.free {
+		return b.free < o.free
+	}
+	return b.cost < o.cost-eps
+}
+
+// Improvement summarizes the moves Improve applied to a line.
+type Improvement struct {
+	Moves []Move
+
+	StationsBefore   int
+	StationsAfter    int
+	SmoothnessBefore float64
+	SmoothnessAfter  float64
+}
+
+// Improve applies moves to an already balanced line for as long as they
+// lower the objective's cost. Each round it first tries to empty the least
+// loaded stations by shifting all of their tasks elsewhere, then tries
+// shifting single tasks and swapping pairs of tasks between stations.
+//
+// Moves are only made between active stations and are kept only if every
+// affected task still satisfies the line's constraints, with its
+// predecessors at the same or earlier stations and its successors at the
+// same or later ones.
+func (l *Line) Improve(obj Objective) (*Improvement, error) {
+	summary := &Improvement{
+		StationsBefore:   l.NActiveStations(),
+		SmoothnessBefore: l.smoothness(),
+	}
+
+	cost := obj.Cost(l)
+	for {
+		moves, err := l.emptyStation(obj, cost)
+		if err != nil {
+			return nil, err
+		}
+
+		if moves == nil {
+			moves, err = l.improveMove(obj, cost)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if moves == nil {
+			break
+		}
+
+		summary.Moves = append(summary.Moves, moves...)
+		cost = obj.Cost(l)
+	}
+
+	summary.StationsAfter = l.NActiveStations()
+	summary.SmoothnessAfter = l.smoothness()
+	return summary, nil
+}
+
+// emptyStation tries to shift every task off one of the line's active
+// stations, from the least loaded up. It returns the moves that emptied the
+// first station it could empty at a lower cost, or nil.
+func (l *Line) emptyStation(obj Objective, cost float64) ([]Move, error) {
+	stations := l.ActiveStations()
+	sort.Slice(stations, func(i, j int) bool {
+		if stations[i].Time() != stations[j].Time() {
+			return stations[i].Time() < stations[j].Time()
+		}
+		return stations[i].ID < stations[j].ID
+	})
+
+	for _, station := range stations {
+		var moves []Move
+		for progress := true; progress && station.NTasks() > 0; {
+			progress = false
+			for _, task := range append([]*Task(nil), station.Tasks()...) {
+				for _, target := range stations {
+					if target == station {
+						continue
+					}
+
+					m := Move{Kind: Shift, Task: task.ID, To: target.ID}
+					ok, err := l.do(&m)
+					if err != nil {
+						return nil, err
+					}
+
+					if ok {
+						moves = append(moves, m)
+						progress = true
+						break
+					}
+				}
+			}
+		}
+
+		if station.NTasks() == 0 && obj.Cost(l) < cost-eps {
+			return moves, nil
+		}
+
+		for i := len(moves) - 1; i >= 0; i-- {
+			err := l.undo(&moves[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// improveMove returns the first shift or swap that lowers the cost, after
+// applying it, or nil.
+func (l *Line) improveMove(obj Objective, cost float64) ([]Move, error) {
+	for _, m := range l.neighborhood(nil, 0) {
+		ok, err := l.do(&m)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		if obj.Cost(l) < cost-eps {
+			return []Move{m}, nil
+		}
+
+		err = l.undo(&m)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
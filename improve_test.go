@@ -0,0 +1,88 @@
+package alb
+
+import "testing"
+
+func TestImproveStations(t *testing.T) {
+	line := newTestLine("TestImproveStations", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+	err := line.Restore(newSnapshot(map[int][]int{1: {1}, 2: {2, 3}, 3: {4, 5}, 4: {6}}))
+	if err != nil {
+		t.Fatalf("line.Restore returned an error, %s", err)
+	}
+
+	summary, err := line.Improve(MinimizeStations)
+	if err != nil {
+		t.Fatalf("Improve returned an error, %s", err)
+	}
+
+	if summary.StationsBefore != 4 || summary.StationsAfter != 2 {
+		t.Errorf("Improve() = 4 -> 2 stations, got %d -> %d", summary.StationsBefore, summary.StationsAfter)
+	}
+
+	if got := line.NActiveStations(); got != 2 {
+		t.Errorf("line.NActiveStations() = 2, got %d", got)
+	}
+
+	if len(summary.Moves) == 0 {
+		t.Error("Improve() returned no moves")
+	}
+
+	for _, station := range line.ActiveStations() {
+		if station.Time() > 10 {
+			t.Errorf("station %d time %.1f exceeds cycle time 10", station.ID, station.Time())
+		}
+	}
+}
+
+func TestImproveKeepsPrecedence(t *testing.T) {
+	var tests = []struct {
+		times    []float64
+		preds    [][2]int
+		assign   map[int][]int
+		stations int
+	}{
+		// Task 2 could fill station 1, but it follows task 3 at station 2.
+		{[]float64{4, 4, 4}, [][2]int{{1, 3}, {3, 2}}, map[int][]int{1: {1}, 2: {3}, 3: {2}}, 2},
+		// Task 3 fits at station 1, but it follows task 2 at station 2, so
+		// task 1 joins task 3 instead.
+		{[]float64{6, 9, 4}, [][2]int{{2, 3}}, map[int][]int{1: {1}, 2: {2}, 3: {3}}, 2},
+	}
+
+	for _, test := range tests {
+		line := newTestLine("TestImproveKeepsPrecedence", test.times, test.preds, 10)
+		err := line.Restore(newSnapshot(test.assign))
+		if err != nil {
+			t.Fatalf("line.Restore returned an error, %s", err)
+		}
+
+		_, err = line.Improve(MinimizeStations)
+		if err != nil {
+			t.Fatalf("Improve returned an error, %s", err)
+		}
+
+		if err := line.Validate(); err != nil {
+			t.Errorf("line.Validate() after Improve = nil, got %s", err)
+		}
+
+		if got := line.NActiveStations(); got != test.stations {
+			t.Errorf("line.NActiveStations() = %d, got %d", test.stations, got)
+		}
+	}
+}
+
+func TestImproveSmoothness(t *testing.T) {
+	line := newTestLine("TestImproveSmoothness", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+	_ = line.BalanceByStationId(LongestTaskTime)
+
+	summary, err := line.Improve(MinimizeSmoothness)
+	if err != nil {
+		t.Fatalf("Improve returned an error, %s", err)
+	}
+
+	if summary.SmoothnessAfter >= summary.SmoothnessBefore {
+		t.Errorf("Improve() smoothness %.2f -> %.2f did not decrease", summary.SmoothnessBefore, summary.SmoothnessAfter)
+	}
+
+	if got := SmoothnessIndex(line, 10); got != summary.SmoothnessAfter {
+		t.Errorf("SmoothnessIndex() = %.2f, got %.2f", summary.SmoothnessAfter, got)
+	}
+}
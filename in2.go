@@ -0,0 +1,246 @@
+package alb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ReadIn2 reads an instance in the IN2 format of the Scholl and Otto SALBP
+// benchmark data sets:
+//
+//	<number of tasks>
+//	<time of task 1>
+//	...
+//	<time of task n>
+//	<predecessor>,<successor>
+//	...
+//	-1,-1
+//
+// Task times may also be given as "<id>,<time>". Fields may be separated by
+// commas, whitespace or both, blank lines are skipped, and anything after a
+// '#' is a comment. The relations end at the -1,-1 terminator or at the end
+// of the file.
+//
+// Lines after the terminator annotate the instance: "<cycle time>" or
+// "<cycle time> <optimal number of stations>" lines are added to the
+// instance's CycleTimes and Optima, and "<key>: <value>" or "<key>=<value>"
+// lines to its Metadata. Errors are *ParseErrors giving the line of the file.
+func ReadIn2(r io.Reader) (*Instance, error) {
+	inst := NewInstance("")
+	byID := make(map[int]*Task)
+
+	const (
+		count = iota
+		times
+		relations
+		annotations
+	)
+	section := count
+	var n int
+
+	scanner := bufio.NewScanner(r)
+	var lineno int
+	for scanner.Scan() {
+		lineno++
+		fail := func(format string, args ...interface{}) error {
+			return &ParseError{Format: "in2", Line: lineno, Err: fmt.Errorf(format, args...)}
+		}
+
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		if section == annotations {
+			err := inst.annotate(text)
+			if err != nil {
+				return nil, fail("%s", err)
+			}
+			continue
+		}
+
+		fields := in2Fields(text)
+		if len(fields) == 0 {
+			return nil, fail("expected a value, got %q", text)
+		}
+
+		switch section {
+		case count:
+			if len(fields) != 1 {
+				return nil, fail("number of tasks: expected 1 field, got %d", len(fields))
+			}
+
+			v, err := strconv.Atoi(fields[0])
+			if err != nil || v < 0 {
+				return nil, fail("number of tasks: invalid count %q", fields[0])
+			}
+
+			n = v
+			section = times
+			if n == 0 {
+				section = relations
+			}
+		case times:
+			id := len(inst.Tasks) + 1
+			timeField := fields[0]
+			switch len(fields) {
+			case 1:
+			case 2:
+				v, err := strconv.Atoi(fields[0])
+				if err != nil {
+					return nil, fail("task id: invalid id %q", fields[0])
+				}
+				id, timeField = v, fields[1]
+			default:
+				return nil, fail("task time: expected 1 or 2 fields, got %d", len(fields))
+			}
+
+			time, err := strconv.ParseFloat(timeField, 64)
+			if err != nil || time < 0 {
+				return nil, fail("task %d: invalid time %q", id, timeField)
+			}
+
+			if _, ok := byID[id]; ok {
+				return nil, fail("task %d: duplicate task", id)
+			}
+
+			task := NewTask(id, time)
+			byID[id] = task
+			inst.Tasks = append(inst.Tasks, task)
+			if len(inst.Tasks) == n {
+				section = relations
+			}
+		case relations:
+			if len(fields) != 2 {
+				return nil, fail("precedence relation: expected 2 fields, got %d", len(fields))
+			}
+
+			predID, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return nil, fail("precedence relation: invalid predecessor %q", fields[0])
+			}
+
+			taskID, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fail("precedence relation: invalid successor %q", fields[1])
+			}
+
+			if predID == -1 && taskID == -1 {
+				section = annotations
+				continue
+			}
+
+			pred, ok := byID[predID]
+			if !ok {
+				return nil, fail("precedence relation: unknown predecessor %d", predID)
+			}
+
+			task, ok := byID[taskID]
+			if !ok {
+				return nil, fail("precedence relation: unknown successor %d", taskID)
+			}
+
+			task.AddPred(pred)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("in2: %s", err)
+	}
+
+	switch section {
+	case count:
+		return nil, errors.New("in2: file is empty")
+	case times:
+		return nil, &ParseError{Format: "in2", Line: lineno,
+			Err: fmt.Errorf("expected %d task times, got %d", n, len(inst.Tasks))}
+	}
+	return inst, nil
+}
+
+// annotate adds an annotation line following the IN2 terminator to the
+// instance.
+func (inst *Instance) annotate(text string) error {
+	if i := strings.IndexAny(text, ":="); i >= 0 {
+		key := strings.TrimSpace(text[:i])
+		if key == "" {
+			return fmt.Errorf("annotation: missing key in %q", text)
+		}
+		inst.Metadata[key] = strings.TrimSpace(text[i+1:])
+		return nil
+	}
+
+	fields := in2Fields(text)
+	if len(fields) == 0 {
+		return fmt.Errorf("annotation: expected a cycle time, got %q", text)
+	}
+	if len(fields) > 2 {
+		return fmt.Errorf("annotation: expected a cycle time and optimum, got %d fields", len(fields))
+	}
+
+	time, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil || time <= 0 {
+		return fmt.Errorf("annotation: invalid cycle time %q", fields[0])
+	}
+
+	var optimum int
+	if len(fields) == 2 {
+		optimum, err = strconv.Atoi(fields[1])
+		if err != nil || optimum <= 0 {
+			return fmt.Errorf("annotation: invalid number of stations %q", fields[1])
+		}
+	}
+
+	inst.addCycleTime(time, optimum)
+	return nil
+}
+
+// in2Fields splits a line on commas and whitespace.
+func in2Fields(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+// WriteIn2 writes the line's tasks and precedence relations in the IN2
+// format read by ReadIn2, ending with the -1,-1 terminator and, if the line
+// has one (see CycleTime), its cycle time. Task times are written as
+// "<id>,<time>" unless the tasks are numbered 1 to n.
+func WriteIn2(w io.Writer, line *Line) error {
+	tasks := line.Tasks()
+	numbered := true
+	for i, task := range tasks {
+		if task.ID != i+1 {
+			numbered = false
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%d\n", len(tasks))
+	for _, task := range tasks {
+		time := strconv.FormatFloat(task.Time(), 'g', -1, 64)
+		if numbered {
+			fmt.Fprintf(bw, "%s\n", time)
+		} else {
+			fmt.Fprintf(bw, "%d,%s\n", task.ID, time)
+		}
+	}
+
+	for _, arc := range newPrecedenceGraph(tasks).Arcs() {
+		fmt.Fprintf(bw, "%d,%d\n", arc[0], arc[1])
+	}
+	fmt.Fprintf(bw, "-1,-1\n")
+	if time, ok := line.CycleTime(); ok {
+		fmt.Fprintf(bw, "%s\n", strconv.FormatFloat(time, 'g', -1, 64))
+	}
+	return bw.Flush()
+}
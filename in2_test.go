@@ -0,0 +1,137 @@
+package alb
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadIn2(t *testing.T) {
+	input := strings.Join([]string{
+		"# A small instance",
+		"4",
+		"3",
+		" 5.5\t",
+		"",
+		"2",
+		"4",
+		"1,2",
+		"1 3  # comment",
+		"2, 4",
+		"3\t4",
+		"-1,-1",
+		"10 2",
+		"12",
+		"source: test",
+	}, "\r\n")
+
+	inst, err := ReadIn2(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadIn2 returned an error, %s", err)
+	}
+
+	if len(inst.Tasks) != 4 || inst.Tasks[1].Time() != 5.5 {
+		t.Fatalf("ReadIn2() = 4 tasks, task 2 time 5.5, got %d tasks", len(inst.Tasks))
+	}
+
+	if got := inst.Tasks[3].Preds(); len(got) != 2 || got[0].ID != 2 || got[1].ID != 3 {
+		t.Errorf("task 4 preds = [2 3], got %v", got)
+	}
+
+	if len(inst.CycleTimes) != 2 || inst.CycleTimes[0] != 10 || inst.CycleTimes[1] != 12 {
+		t.Errorf("inst.CycleTimes = [10 12], got %v", inst.CycleTimes)
+	}
+
+	if len(inst.Optima) != 1 || inst.Optima[10] != 2 {
+		t.Errorf("inst.Optima = map[10:2], got %v", inst.Optima)
+	}
+
+	if inst.Metadata["source"] != "test" {
+		t.Errorf("inst.Metadata[source] = test, got %q", inst.Metadata["source"])
+	}
+}
+
+func TestReadIn2Errors(t *testing.T) {
+	var tests = []struct {
+		input string
+		line  int
+	}{
+		{"2\n1\nx\n", 3},
+		{"2\n1\n2\n1,3\n", 4},
+		{"2\n1\n2\n1,2,3\n", 4},
+		{"two\n", 1},
+		{"2\n1\n2\n-1,-1\n\nfoo bar baz\n", 6},
+		{"3\n1\n2\n", 3},
+		{"2\n,\n2\n", 2},
+		{"1\n1\n-1,-1\n,\n", 4},
+	}
+
+	for _, test := range tests {
+		_, err := ReadIn2(strings.NewReader(test.input))
+		perr, ok := err.(*ParseError)
+		if !ok || perr.Line != test.line {
+			t.Errorf("ReadIn2(%q) = error at line %d, got %v", test.input, test.line, err)
+		}
+	}
+
+	if _, err := ReadIn2(strings.NewReader("\n# empty\n")); err == nil {
+		t.Errorf("ReadIn2(empty) = error, got nil")
+	}
+}
+
+func TestWriteIn2(t *testing.T) {
+	file, err := os.Open("specs/buxey.in2")
+	if err != nil {
+		t.Fatalf("opening buxey.in2 returned an error, %s", err)
+	}
+	defer file.Close()
+
+	inst, err := ReadIn2(file)
+	if err != nil {
+		t.Fatalf("ReadIn2 returned an error, %s", err)
+	}
+
+	line, err := inst.Line()
+	if err != nil {
+		t.Fatalf("inst.Line() returned an error, %s", err)
+	}
+
+	line.AddConstraint(&RestrictedStationTime{Time: 33})
+
+	var buf bytes.Buffer
+	err = WriteIn2(&buf, line)
+	if err != nil {
+		t.Fatalf("WriteIn2 returned an error, %s", err)
+	}
+
+	again, err := ReadIn2(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadIn2(WriteIn2()) returned an error, %s", err)
+	}
+
+	if len(again.Tasks) != 29 || line.NStations() != 29 {
+		t.Fatalf("ReadIn2(WriteIn2(buxey)) = 29 tasks, got %d", len(again.Tasks))
+	}
+
+	if len(again.CycleTimes) != 1 || again.CycleTimes[0] != 33 {
+		t.Errorf("ReadIn2(WriteIn2(buxey)).CycleTimes = [33], got %v", again.CycleTimes)
+	}
+
+	for i, task := range inst.Tasks {
+		other := again.Tasks[i]
+		if other.ID != task.ID || other.Time() != task.Time() || len(other.Preds()) != len(task.Preds()) {
+			t.Errorf("task %d = time %.2f %d preds, got task %d time %.2f %d preds",
+				task.ID, task.Time(), len(task.Preds()), other.ID, other.Time(), len(other.Preds()))
+		}
+	}
+
+	// Tasks not numbered 1 to n keep their ids.
+	line = newTestLine("TestWriteIn2", []float64{1, 2}, [][2]int{{1, 2}}, 10)
+	_ = line.RemoveTask(1)
+	buf.Reset()
+	_ = WriteIn2(&buf, line)
+	if want := "1\n2,2\n-1,-1\n10\n"; buf.String() != want {
+		t.Errorf("WriteIn2() = %q, got %q", want, buf.String())
+	}
+}
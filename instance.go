@@ -0,0 +1,68 @@
+package alb
+
+import "fmt"
+
+// Instance is a line balancing problem read from a data file: its tasks,
+// already linked to their predecessors, and whatever else the file gives.
+type Instance struct {
+	Name string
+
+	// Tasks are the instance's tasks, in the order the file lists them.
+	Tasks []*Task
+
+	// CycleTimes are the cycle times the file gives the instance, and
+	// Optima the known optimal number of stations for some of them.
+	CycleTimes []float64
+	Optima     map[float64]int
+
+	// Metadata holds any other annotations in the file.
+	Metadata map[string]string
+}
+
+// NewInstance returns an initialized Instance pointer.
+func NewInstance(name string) *Instance {
+	return &Instance{
+		Name:     name,
+		Optima:   make(map[float64]int),
+		Metadata: make(map[string]string),
+	}
+}
+
+// Line returns a line with the instance's tasks and one station per task,
+// numbered from 1, which is enough to balance it at any feasible cycle time.
+func (inst *Instance) Line() (*Line, error) {
+	line := NewLine(inst.Name)
+	err := line.AddTasks(inst.Tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range inst.Tasks {
+		err := line.AddStation(NewStation(i + 1))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return line, nil
+}
+
+// addCycleTime records a cycle time given by the file, with the optimal
+// number of stations if it is known (greater than zero).
+func (inst *Instance) addCycleTime(time float64, optimum int) {
+	inst.CycleTimes = append(inst.CycleTimes, time)
+	if optimum > 0 {
+		inst.Optima[time] = optimum
+	}
+}
+
+// ParseError is an error reading a data file, at the given line of the file
+// (counting from 1).
+type ParseError struct {
+	Format string
+	Line   int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: line %d: %s", e.Format, e.Line, e.Err)
+}
@@ -1,6 +1,7 @@
 package alb
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"sort"
@@ -8,7 +9,16 @@ import (
 
 // Line is an assembly line with stations and tasks.
 type Line struct {
-	Name        string
+	Name string
+
+	// Direction is the direction the balance methods fill the line's
+	// stations in. The zero value is Forward. It is followed by
+	// BalanceByStationId, BalanceByShortestStationTime (except
+	// Bidirectional) and the methods built on them; branch and bound, beam
+	// search and ant colony optimization return an error for other
+	// directions.
+	Direction Direction
+
 	stations    map[int]*Station
 	tasks       map[int]*Task
 	constraints []Constraint
@@ -51,6 +61,17 @@ func (l *Line) ReplaceConstraint(c Constraint) error {
 	return nil
 }
 
+// CycleTime returns the cycle time enforced by the line's
+// RestrictedStationTime constraint, if the line has one.
+func (l *Line) CycleTime() (float64, bool) {
+	for _, constraint := range l.constraints {
+		if c, ok := constraint.(*RestrictedStationTime); ok {
+			return c.Time, true
+		}
+	}
+	return 0, false
+}
+
 // RemoveConstraints removes all constraints from the line.
 func (l *Line) RemoveConstraints() {
 	l.constraints = nil
@@ -106,10 +127,10 @@ func (l *Line) NStations() int {
 	return len(l.stations)
 }
 
-// ActiveStations returns all active stations on the line.
+// ActiveStations returns all active stations on the line, sorted by station ID.
 func (l *Line) ActiveStations() []*Station {
 	var stations []*Station
-	for _, station := range l.stations {
+	for _, station := range l.Stations() {
 		if station.Active() {
 			stations = append(stations, station)
 		}
@@ -197,6 +218,38 @@ func (l *Line) AddTask(task *Task) error {
 	return nil
 }
 
+// RemoveTask removes a task from the line. The task is withdrawn from its
+// station, and its links to its predecessors and successors are removed.
+// Each of its predecessors becomes a predecessor of each of its successors,
+// so precedence through the task is kept.
+func (l *Line) RemoveTask(id int) error {
+	task := l.Task(id)
+	if task == nil {
+		return fmt.Errorf("line has no task %d", id)
+	}
+
+	if station := task.Assignment(); station != nil {
+		err := station.WithdrawTask(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, succ := range task.Succs() {
+		for _, pred := range task.Preds() {
+			succ.AddPred(pred)
+		}
+		succ.RemovePred(id)
+	}
+
+	for _, pred := range task.Preds() {
+		task.RemovePred(pred.ID)
+	}
+
+	delete(l.tasks, id)
+	return nil
+}
+
 // AddTasks adds stations to the line.
 func (l *Line) AddTasks(tasks []*Task) error {
 	for _, task := range tasks {
@@ -238,6 +291,170 @@ func (l *Line) UnassignTasks() error {
 	return nil
 }
 
+// reset unassigns all tasks and disables all stations on the line.
+func (l *Line) reset() error {
+	err := l.UnassignTasks()
+	if err != nil {
+		return err
+	}
+
+	for _, station := range l.stations {
+		station.Disable()
+	}
+	return nil
+}
+
+// Clone returns a copy of the line that can be balanced independently of
+// it: its tasks and their precedence relations, its stations and its current
+// assignment are copied.
+// Constraints are not copied but shared with the copy, since balancing does
+// not modify them; replace them on the copy with ReplaceConstraint rather
+// than modifying them in place.
+func (l *Line) Clone() (*Line, error) {
+	c := NewLine(l.Name)
+	c.Direction = l.Direction
+	c.constraints = append([]Constraint(nil), l.constraints...)
+
+	for id, task := range l.tasks {
+		c.tasks[id] = NewTask(task.ID, task.time)
+		c.tasks[id].Name = task.Name
+		if task.Attributes != nil {
+			c.tasks[id].Attributes = make(map[string]string)
+			for k, v := range task.Attributes {
+				c.tasks[id].Attributes[k] = v
+			}
+		}
+	}
+
+	// Predecessors and successors that are not on the line are copied too,
+	// but not added to the copy.
+	others := make(map[*Task]*Task)
+	copyOf := func(task *Task) *Task {
+		if l.tasks[task.ID] == task {
+			return c.tasks[task.ID]
+		}
+		if _, ok := others[task]; !ok {
+			others[task] = NewTask(task.ID, task.time)
+		}
+		return others[task]
+	}
+
+	for id, task := range l.tasks {
+		for _, pred := range task.predecessors {
+			c.tasks[id].AddPred(copyOf(pred))
+		}
+		for _, succ := range task.successors {
+			copyOf(succ).AddPred(c.tasks[id])
+		}
+	}
+
+	for id, station := range l.stations {
+		s := NewStation(station.ID)
+		s.active = station.active
+		c.stations[id] = s
+	}
+
+	for stationID, station := range l.stations {
+		for _, task := range station.Tasks() {
+			t, ok := c.tasks[task.ID]
+			if !ok {
+				return nil, fmt.Errorf("line has no task %d", task.ID)
+			}
+
+			err := c.stations[stationID].AssignTask(t)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return c, nil
+}
+
+// Snapshot is a saved assignment of a line's tasks to its stations, taken
+// with Line.Snapshot and rolled back to with Line.Restore.
+type Snapshot struct {
+	tasks  map[int][]int
+	active map[int]bool
+}
+
+// newSnapshot returns a snapshot assigning the given task ids to each
+// station, keyed by station id, with exactly those stations active.
+func newSnapshot(tasks map[int][]int) *Snapshot {
+	s := &Snapshot{tasks: tasks, active: make(map[int]bool)}
+	for id := range tasks {
+		s.active[id] = true
+	}
+	return s
+}
+
+// Snapshot saves the line's current assignment: the tasks assigned to each
+// station, in order, and which stations are active. It can be restored on
+// the line or on any clone of it.
+func (l *Line) Snapshot() *Snapshot {
+	s := &Snapshot{
+		tasks:  make(map[int][]int),
+		active: make(map[int]bool),
+	}
+
+	for _, station := range l.stations {
+		if station.Active() {
+			s.active[station.ID] = true
+		}
+
+		if station.NTasks() == 0 {
+			continue
+		}
+
+		ids := make([]int, 0, station.NTasks())
+		for _, task := range station.Tasks() {
+			ids = append(ids, task.ID)
+		}
+		s.tasks[station.ID] = ids
+	}
+	return s
+}
+
+// Restore unassigns all tasks on the line and reassigns them as they were
+// when the snapshot was taken. The line is left unchanged if the snapshot
+// has stations or tasks the line does not have.
+func (l *Line) Restore(s *Snapshot) error {
+	for id := range s.active {
+		if l.Station(id) == nil {
+			return fmt.Errorf("line has no station %d", id)
+		}
+	}
+	for stationID, taskIDs := range s.tasks {
+		if l.Station(stationID) == nil {
+			return fmt.Errorf("line has no station %d", stationID)
+		}
+		for _, taskID := range taskIDs {
+			if l.Task(taskID) == nil {
+				return fmt.Errorf("line has no task %d", taskID)
+			}
+		}
+	}
+
+	err := l.reset()
+	if err != nil {
+		return err
+	}
+
+	for stationID, taskIDs := range s.tasks {
+		station := l.Station(stationID)
+		for _, taskID := range taskIDs {
+			err := station.AssignTask(l.Task(taskID))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for id := range s.active {
+		l.Station(id).Activate()
+	}
+	return nil
+}
+
 // ValidAssignment checks to see if the given task can be assigned to the
 // given station. Validity is dependent on the line's current constraints.
 func (l *Line) ValidAssignment(taskID, stationID int) bool {
@@ -257,6 +474,48 @@ func (l *Line) ValidAssignment(taskID, stationID int) bool {
 	return true
 }
 
+// ExplainAssignment returns the reasons the given task cannot be assigned to
+// the given station, one for each of the line's constraints it fails
+// (without repeating a reason), or nil if the assignment is valid.
+// Constraints that implement Explainer give their own reasons; others are
+// reported by name.
+func (l *Line) ExplainAssignment(taskID, stationID int) []string {
+	task := l.Task(taskID)
+	if task == nil {
+		return []string{fmt.Sprintf("line has no task %d", taskID)}
+	}
+
+	station := l.Station(stationID)
+	if station == nil {
+		return []string{fmt.Sprintf("line has no station %d", stationID)}
+	}
+
+	var reasons []string
+	seen := make(map[string]bool)
+	for _, constraint := range l.constraints {
+		if constraint.Valid(task, station) {
+			continue
+		}
+
+		reason := explain(constraint, task, station)
+		if !seen[reason] {
+			reasons = append(reasons, reason)
+			seen[reason] = true
+		}
+	}
+	return reasons
+}
+
+// explain returns the reason an assignment fails a constraint.
+func explain(c Constraint, task *Task, station *Station) string {
+	if e, ok := c.(Explainer); ok {
+		if reason := e.Explain(task, station); reason != "" {
+			return reason
+		}
+	}
+	return fmt.Sprintf("violates %s", constraintName(c))
+}
+
 // ValidAssignments returns all tasks on the line that can be assigned
 // to the given station. It calls ValidAssignment for each task to
 // check that the assignment would not violate any of the line's constraints.
@@ -274,26 +533,56 @@ func (l *Line) ValidAssignments(stationID int) []*Task {
 // assignments have been made. It assigns tasks to the line's station by
 // iterating over the stations in order by their id. It uses the given
 // heuristic function to determine the order that valid tasks are assigned.
+//
+// The line's Direction changes the order the stations are filled in, see
+// Reverse and Bidirectional.
 func (l *Line) BalanceByStationId(fn Heuristic) error {
-	for _, station := range l.Stations() {
-		didProgress := false
-		candidates := l.ValidAssignments(station.ID)
-		for len(candidates) > 0 {
-			didProgress = true
-			best := fn(candidates)
-			err := station.AssignTask(best)
-			if err != nil {
-				return err
-			}
+	pick := func(station *Station, candidates []*Task) *Task {
+		return fn(candidates)
+	}
+
+	switch l.Direction {
+	case Reverse:
+		return l.reverse(func(m *Line) error { return m.fillStations(pick) })
+	case Bidirectional:
+		return l.fillBothEnds(pick)
+	}
+
+	return l.fillStations(pick)
+}
 
-			candidates = l.ValidAssignments(station.ID)
+// fillStations is BalanceByStationId with a pick function that is also
+// given the station being filled.
+func (l *Line) fillStations(pick func(*Station, []*Task) *Task) error {
+	for _, station := range l.Stations() {
+		err := l.fillStation(station, pick)
+		if err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
 
-		if didProgress {
-			station.Activate()
+// fillStation assigns tasks to the station until it has no valid
+// assignments left, activating it if any task was assigned.
+func (l *Line) fillStation(station *Station, pick func(*Station, []*Task) *Task) error {
+	didProgress := false
+	candidates := l.ValidAssignments(station.ID)
+	for len(candidates) > 0 {
+		didProgress = true
+		best := pick(station, candidates)
+		err := station.AssignTask(best)
+		if err != nil {
+			return err
 		}
+
+		candidates = l.ValidAssignments(station.ID)
 	}
 
+	if didProgress {
+		station.Activate()
+	}
 	return nil
 }
 
@@ -304,7 +593,21 @@ func (l *Line) BalanceByStationId(fn Heuristic) error {
 //
 // NOTE: ValidateParams() must be called to use this balance method. See
 // note in code for further explanation.
+//
+// A Reverse line is balanced on its reversed precedence graph. Bidirectional
+// balancing is not supported by this method.
 func (l *Line) BalanceByShortestStationTime(fn Heuristic) error {
+	switch l.Direction {
+	case Reverse:
+		return l.reverse(func(m *Line) error { return m.balanceByShortestStationTime(fn) })
+	case Bidirectional:
+		return errors.New("bidirectional balancing is not supported by shortest station time")
+	}
+
+	return l.balanceByShortestStationTime(fn)
+}
+
+func (l *Line) balanceByShortestStationTime(fn Heuristic) error {
 	for {
 		for {
 			didProgress := false
@@ -369,8 +672,9 @@ func (l *Line) BalanceByShortestStationTime(fn Heuristic) error {
 		// that the line's global capacity (n stations * cycle time) can hold
 		// the line's global work (total task time).
 		//
-		// If ValidateParams() is not called or its 2 checks are violated, this
-		// balance method could be unbounded.
+		// If ValidateParams() is not called or its 2 checks are violated, or
+		// the line's precedence graph has a cycle (see ValidatePrecedence),
+		// tasks can be left free once every station is active.
 		if l.NFreeTasks() == 0 {
 			return nil
 		}
@@ -385,5 +689,8 @@ func (l *Line) BalanceByShortestStationTime(fn Heuristic) error {
 			}
 			continue
 		}
+
+		// Every station is active and none of them can take a free task.
+		return nil
 	}
 }
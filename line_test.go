@@ -161,3 +161,151 @@ func TestTasksInLine(t *testing.T) {
 // TODO(ah): test that tasks returned in order by id
 //func TestTasksInLineInOrder(t *testing.T) {
 //}
+
+func TestCloneIsIndependent(t *testing.T) {
+	line := newTestLine("TestCloneIsIndependent", []float64{5, 4, 3}, [][2]int{{1, 2}}, 10)
+	err := line.BalanceByStationId(LongestTaskTime)
+	if err != nil {
+		t.Fatalf("BalanceByStationId returned an error, %s", err)
+	}
+
+	c, err := line.Clone()
+	if err != nil {
+		t.Fatalf("Clone returned an error, %s", err)
+	}
+
+	if c.Task(2).Pred(1) != c.Task(1) {
+		t.Errorf("clone.Task(2).Pred(1) = clone.Task(1), got %v", c.Task(2).Pred(1))
+	}
+
+	if c.Task(1) == line.Task(1) || c.Station(1) == line.Station(1) {
+		t.Errorf("Clone() = new tasks and stations, got shared pointers")
+	}
+
+	if c.NActiveStations() != line.NActiveStations() || c.NFreeTasks() != 0 {
+		t.Errorf("clone.NActiveStations() = %d 0 free, got %d %d free",
+			line.NActiveStations(), c.NActiveStations(), c.NFreeTasks())
+	}
+
+	err = c.UnassignTasks()
+	if err != nil {
+		t.Fatalf("UnassignTasks returned an error, %s", err)
+	}
+
+	if line.NFreeTasks() != 0 {
+		t.Errorf("line.NFreeTasks() after unassigning the clone = 0, got %d", line.NFreeTasks())
+	}
+}
+
+func TestSnapshotRestore(t *testing.T) {
+	line := newTestLine("TestSnapshotRestore", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+	err := line.BalanceByStationId(LongestTaskTime)
+	if err != nil {
+		t.Fatalf("BalanceByStationId returned an error, %s", err)
+	}
+
+	snapshot := line.Snapshot()
+	want := line.Station(1).String()
+
+	err = line.BalanceByStationId(ShortestTaskTime)
+	if err == nil {
+		err = line.UnassignTasks()
+	}
+	if err != nil {
+		t.Fatalf("rebalancing returned an error, %s", err)
+	}
+
+	err = line.Restore(snapshot)
+	if err != nil {
+		t.Fatalf("Restore returned an error, %s", err)
+	}
+
+	if got := line.Station(1).String(); got != want {
+		t.Errorf("line.Station(1) after Restore = %q, got %q", want, got)
+	}
+
+	if line.NActiveStations() != 3 || line.NFreeTasks() != 0 {
+		t.Errorf("line after Restore = 3 stations 0 free, got %d stations %d free",
+			line.NActiveStations(), line.NFreeTasks())
+	}
+
+	c, err := line.Clone()
+	if err != nil {
+		t.Fatalf("Clone returned an error, %s", err)
+	}
+
+	err = c.Restore(&Snapshot{tasks: map[int][]int{99: {1}}})
+	if err == nil {
+		t.Errorf("Restore(unknown station) = error, got nil")
+	}
+}
+
+type oddStations struct{}
+
+func (c *oddStations) Valid(task *Task, station *Station) bool {
+	return station.ID%2 == 1
+}
+
+func TestExplainAssignment(t *testing.T) {
+	line := newTestLine("TestExplainAssignment", []float64{58, 9, 3}, [][2]int{{3, 2}}, 60)
+	line.AddConstraint(&PredecessorsInPriorStations{})
+	line.AddConstraint(&oddStations{})
+	_ = line.Station(1).AssignTask(line.Task(1))
+
+	var tests = []struct {
+		task, station int
+		want          []string
+	}{
+		{3, 3, nil},
+		{2, 1, []string{"station time 58+9 exceeds 60", "predecessor 3 unassigned"}},
+		{1, 3, []string{"task 1 already assigned to station 1"}},
+		{3, 2, []string{"violates oddStations"}},
+		{9, 1, []string{"line has no task 9"}},
+		{3, 9, []string{"line has no station 9"}},
+	}
+
+	for _, test := range tests {
+		got := line.ExplainAssignment(test.task, test.station)
+		if len(got) != len(test.want) {
+			t.Errorf("line.ExplainAssignment(%d, %d) = %q, got %q", test.task, test.station, test.want, got)
+			continue
+		}
+
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("line.ExplainAssignment(%d, %d)[%d] = %q, got %q", test.task, test.station, i, test.want[i], got[i])
+			}
+		}
+	}
+}
+
+func TestRemoveTask(t *testing.T) {
+	line := newTestLine("TestRemoveTask", []float64{1, 2, 3}, [][2]int{{1, 2}, {2, 3}}, 10)
+	_ = line.Station(1).AssignTask(line.Task(2))
+	task := line.Task(2)
+
+	err := line.RemoveTask(2)
+	if err != nil {
+		t.Fatalf("line.RemoveTask(2) returned an error, %s", err)
+	}
+
+	if line.Task(2) != nil || line.Station(1).NTasks() != 0 || task.IsAssigned() {
+		t.Errorf("line.RemoveTask(2) = removed and withdrawn, got task %v station %v", line.Task(2), line.Station(1))
+	}
+
+	if line.Task(1).Succ(2) != nil || line.Task(3).Pred(2) != nil {
+		t.Errorf("line.RemoveTask(2) = unlinked, got succ %v pred %v", line.Task(1).Succ(2), line.Task(3).Pred(2))
+	}
+
+	if line.Task(3).Pred(1) != line.Task(1) || line.Task(1).Succ(3) != line.Task(3) {
+		t.Errorf("line.RemoveTask(2) = task 1 precedes task 3, got preds %v", line.Task(3).Preds())
+	}
+
+	if len(task.Preds()) != 0 || len(task.Succs()) != 0 {
+		t.Errorf("removed task = no preds or succs, got preds %v succs %v", task.Preds(), task.Succs())
+	}
+
+	if err := line.RemoveTask(2); err == nil {
+		t.Errorf("line.RemoveTask(2) twice = error, got nil")
+	}
+}
@@ -0,0 +1,226 @@
+package alb
+
+import "fmt"
+
+// MoveKind is the kind of change a Move makes to a balanced line.
+type MoveKind int
+
+const (
+	// Shift moves a task to another station.
+	Shift MoveKind = iota
+
+	// Swap exchanges two tasks assigned to different stations.
+	Swap
+)
+
+func (k MoveKind) String() string {
+	switch k {
+	case Shift:
+		return "shift"
+	case Swap:
+		return "swap"
+	}
+	return fmt.Sprintf("MoveKind(%d)", int(k))
+}
+
+// Move relocates assigned tasks between stations of a balanced line.
+type Move struct {
+	Kind MoveKind
+
+	// Task is moved from station From to station To.
+	Task int
+	From int
+	To   int
+
+	// Other is, for swaps, the task moved from station To to station From.
+	Other int
+
+	// Positions of the tasks in their stations before the move, to undo it.
+	taskIdx  int
+	otherIdx int
+}
+
+// String converts the move to a string representation.
+func (m Move) String() string {
+	if m.Kind == Swap {
+		return fmt.Sprintf("swap task %d at station %d with task %d at station %d", m.Task, m.From, m.Other, m.To)
+	}
+	return fmt.Sprintf("shift task %d from station %d to station %d", m.Task, m.From, m.To)
+}
+
+// do applies the move to the line if every task it affects still satisfies
+// the line's constraints and precedence order afterwards, and reports
+// whether it was applied.
+// m.From is set from the task's current assignment. A station left without
+// tasks is disabled. Tasks can only be moved to active stations.
+func (l *Line) do(m *Move) (bool, error) {
+	task := l.Task(m.Task)
+	to := l.Station(m.To)
+	if task == nil || to == nil || !to.Active() || !task.IsAssigned() || task.Assignment() == to {
+		return false, nil
+	}
+
+	from := task.Assignment()
+	m.From = from.ID
+	m.taskIdx = from.index(task.ID)
+
+	var other *Task
+	if m.Kind == Swap {
+		other = l.Task(m.Other)
+		if other == nil || other.Assignment() != to {
+			return false, nil
+		}
+		m.otherIdx = to.index(other.ID)
+	}
+
+	err := from.WithdrawTask(task.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if other != nil {
+		err = to.WithdrawTask(other.ID)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	ok := l.ValidAssignment(task.ID, to.ID)
+	if ok {
+		err = to.AssignTask(task)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if ok && other != nil {
+		ok = l.ValidAssignment(other.ID, from.ID)
+		if ok {
+			err = from.AssignTask(other)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if ok {
+		ok, err = l.consistent(l.affected(from, to, task, other))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if !ok {
+		return false, l.restore(m, from, to, task, other)
+	}
+
+	if from.NTasks() == 0 {
+		from.Disable()
+	}
+	return true, nil
+}
+
+// undo reverts a move previously applied by do.
+func (l *Line) undo(m *Move) error {
+	var other *Task
+	if m.Kind == Swap {
+		other = l.Task(m.Other)
+	}
+	return l.restore(m, l.Station(m.From), l.Station(m.To), l.Task(m.Task), other)
+}
+
+// restore puts the tasks of a move back where they were before it.
+func (l *Line) restore(m *Move, from, to *Station, task, other *Task) error {
+	if task.Assignment() == to {
+		err := to.WithdrawTask(task.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if other != nil {
+		if other.Assignment() == from {
+			err := from.WithdrawTask(other.ID)
+			if err != nil {
+				return err
+			}
+		}
+		to.insertTask(m.otherIdx, other)
+	}
+
+	from.insertTask(m.taskIdx, task)
+	from.Activate()
+	return nil
+}
+
+// affected returns the tasks whose constraints a move between the stations
+// may have changed: the tasks at either station and the successors of the
+// moved tasks.
+func (l *Line) affected(from, to *Station, moved ...*Task) []*Task {
+	tasks := append([]*Task(nil), from.Tasks()...)
+	tasks = append(tasks, to.Tasks()...)
+	for _, task := range l.tasks {
+		for _, m := range moved {
+			if m != nil && task.Pred(m.ID) != nil {
+				tasks = append(tasks, task)
+				break
+			}
+		}
+	}
+	return tasks
+}
+
+// consistent checks that each assigned task would still be a valid
+// assignment to its station under the line's constraints, as if it were
+// being assigned now, and that it is still in precedence order.
+func (l *Line) consistent(tasks []*Task) (bool, error) {
+	for _, task := range tasks {
+		station := task.Assignment()
+		if station == nil {
+			continue
+		}
+
+		if !inPrecedenceOrder(task, station) {
+			return false, nil
+		}
+
+		i := station.index(task.ID)
+		err := station.WithdrawTask(task.ID)
+		if err != nil {
+			return false, err
+		}
+
+		ok := true
+		for _, constraint := range l.constraints {
+			if !constraint.Valid(task, station) {
+				ok = false
+				break
+			}
+		}
+
+		station.insertTask(i, task)
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// inPrecedenceOrder reports whether the task's assigned predecessors are at
+// the station or earlier ones, and its assigned successors at the station or
+// later ones. The line's constraints need not check this: filling stations
+// in order keeps it, but moving tasks between stations does not.
+func inPrecedenceOrder(task *Task, station *Station) bool {
+	for _, pred := range task.Preds() {
+		if s := pred.Assignment(); s != nil && s.ID > station.ID {
+			return false
+		}
+	}
+
+	for _, succ := range task.Succs() {
+		if s := succ.Assignment(); s != nil && s.ID < station.ID {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,171 @@
+package alb
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PortfolioRun describes one heuristic and balance method run by
+// BalancePortfolio.
+type PortfolioRun struct {
+	// Heuristic is the name of the heuristic or priority rule, and Method
+	// either "station" (BalanceByStationId) or "shortest"
+	// (BalanceByShortestStationTime).
+	Heuristic string
+	Method    string
+
+	Stations   int
+	FreeTasks  int
+	Smoothness float64
+	Elapsed    time.Duration
+
+	// Err is the error the run failed with, if any.
+	Err error
+}
+
+// PortfolioResult describes the outcome of BalancePortfolio.
+type PortfolioResult struct {
+	// Runs are sorted by heuristic and then method.
+	Runs []PortfolioRun
+
+	// Best is the run whose balance was assigned to the line, or nil if
+	// every run failed.
+	Best *PortfolioRun
+
+	// Solution is the balance assigned to the line.
+	Solution *Solution
+
+	Elapsed time.Duration
+}
+
+// portfolioMethods are the balance methods every heuristic is run with.
+var portfolioMethods = map[string]func(*Line, Heuristic) error{
+	"station":  (*Line).BalanceByStationId,
+	"shortest": (*Line).BalanceByShortestStationTime,
+}
+
+// BalancePortfolio balances a copy of the line with every registered
+// heuristic in Heuristics and PriorityRules, using both BalanceByStationId
+// and BalanceByShortestStationTime, running concurrently. The best balance
+// (fewest free tasks, then fewest stations, then the lowest smoothness
+// index) is assigned to the line.
+//
+// A failed run is reported in its PortfolioRun and does not stop the others.
+// A run whose balance breaks precedence or the line's constraints fails
+// with a *ValidationError, so the best balance is always a valid one, though
+// it may leave tasks free.
+//
+// BalanceByShortestStationTime can assign a task to an earlier station than
+// its predecessors. Under PredecessorsStartToStart, rather than
+// PredecessorsInPriorStations, its runs are therefore expected to fail on
+// most lines with precedence relations.
+//
+// BalancePortfolio only returns an error if no run succeeded.
+func (l *Line) BalancePortfolio() (*PortfolioResult, error) {
+	start := time.Now()
+
+	type job struct {
+		heuristic string
+		method    string
+		line      *Line
+	}
+
+	var jobs []*job
+	for name := range Heuristics {
+		for method := range portfolioMethods {
+			jobs = append(jobs, &job{heuristic: name, method: method})
+		}
+	}
+	for name := range PriorityRules {
+		for method := range portfolioMethods {
+			jobs = append(jobs, &job{heuristic: name, method: method})
+		}
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].heuristic != jobs[j].heuristic {
+			return jobs[i].heuristic < jobs[j].heuristic
+		}
+		return jobs[i].method < jobs[j].method
+	})
+
+	result := &PortfolioResult{Runs: make([]PortfolioRun, len(jobs))}
+	for i, j := range jobs {
+		c, err := l.Clone()
+		if err != nil {
+			return nil, err
+		}
+
+		err = c.reset()
+		if err != nil {
+			return nil, err
+		}
+
+		j.line = c
+		result.Runs[i] = PortfolioRun{Heuristic: j.heuristic, Method: j.method}
+	}
+
+	var wg sync.WaitGroup
+	for i, j := range jobs {
+		wg.Add(1)
+		go func(run *PortfolioRun, j *job) {
+			defer wg.Done()
+			began := time.Now()
+
+			fn, ok := Heuristics[j.heuristic]
+			if !ok {
+				fn = PriorityRules[j.heuristic](j.line).Heuristic()
+			}
+
+			run.Err = portfolioMethods[j.method](j.line, fn)
+			run.Elapsed = time.Since(began)
+			if run.Err != nil {
+				return
+			}
+
+			if violations := j.line.assignmentViolations(); len(violations) > 0 {
+				run.Err = &ValidationError{Violations: violations}
+				return
+			}
+
+			score := j.line.score()
+			run.Stations = score.stations
+			run.FreeTasks = score.free
+			run.Smoothness = score.smoothness
+		}(&result.Runs[i], j)
+	}
+	wg.Wait()
+
+	var best *job
+	var bestScore balanceScore
+	for i, j := range jobs {
+		if result.Runs[i].Err != nil {
+			continue
+		}
+
+		score := j.line.score()
+		if best == nil || score.less(bestScore) {
+			best = j
+			bestScore = score
+			result.Best = &result.Runs[i]
+		}
+	}
+
+	if best == nil {
+		if len(jobs) == 0 {
+			return nil, errors.New("portfolio has no heuristics")
+		}
+		return nil, result.Runs[0].Err
+	}
+
+	err := l.Restore(best.line.Snapshot())
+	if err != nil {
+		return nil, err
+	}
+
+	result.Solution = l.solution(best.method, "heuristic", best.heuristic)
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package alb
+
+import (
+	"os"
+	"testing"
+)
+
+func TestBalancePortfolio(t *testing.T) {
+	preds := [][2]int{{1, 3}, {2, 3}}
+	line := newTestLine("TestBalancePortfolio", []float64{5, 4, 3, 3, 3, 2}, preds, 10)
+
+	result, err := line.BalancePortfolio()
+	if err != nil {
+		t.Fatalf("BalancePortfolio returned an error, %s", err)
+	}
+
+	want := 2 * (len(Heuristics) + len(PriorityRules))
+	if len(result.Runs) != want {
+		t.Errorf("len(result.Runs) = %d, got %d", want, len(result.Runs))
+	}
+
+	if result.Best == nil {
+		t.Fatalf("result.Best = a run, got nil")
+	}
+
+	for _, run := range result.Runs {
+		if run.Err != nil {
+			t.Errorf("%s %s returned an error, %s", run.Heuristic, run.Method, run.Err)
+			continue
+		}
+
+		if run.FreeTasks < result.Best.FreeTasks ||
+			(run.FreeTasks == result.Best.FreeTasks && run.Stations < result.Best.Stations) {
+			t.Errorf("%s %s beat the best run %s %s", run.Heuristic, run.Method,
+				result.Best.Heuristic, result.Best.Method)
+		}
+	}
+
+	if got := line.NActiveStations(); got != result.Best.Stations {
+		t.Errorf("line.NActiveStations() = %d, got %d", result.Best.Stations, got)
+	}
+
+	if got := line.NFreeTasks(); got != result.Best.FreeTasks {
+		t.Errorf("line.NFreeTasks() = %d, got %d", result.Best.FreeTasks, got)
+	}
+
+	if got := result.Solution.Metadata["heuristic"]; got != result.Best.Heuristic {
+		t.Errorf("result.Solution.Metadata[heuristic] = %s, got %s", result.Best.Heuristic, got)
+	}
+}
+
+func TestBalancePortfolioValid(t *testing.T) {
+	file, err := os.Open("specs/buxey.in2")
+	if err != nil {
+		t.Fatalf("opening buxey.in2 returned an error, %s", err)
+	}
+	defer file.Close()
+
+	inst, err := ReadIn2(file)
+	if err != nil {
+		t.Fatalf("ReadIn2 returned an error, %s", err)
+	}
+
+	line, err := inst.Line()
+	if err != nil {
+		t.Fatalf("inst.Line() returned an error, %s", err)
+	}
+
+	// The balance command's default constraints, under which the shortest
+	// station method can assign tasks before their predecessors.
+	line.AddConstraints([]Constraint{
+		&SingleTaskAssignment{},
+		&RestrictedStationTime{Time: 33},
+		&PredecessorsStartToStart{},
+	})
+
+	result, err := line.BalancePortfolio()
+	if err != nil {
+		t.Fatalf("BalancePortfolio returned an error, %s", err)
+	}
+
+	invalid := 0
+	for _, run := range result.Runs {
+		if _, ok := run.Err.(*ValidationError); ok {
+			invalid++
+		}
+	}
+	if invalid == 0 {
+		t.Errorf("invalid runs = at least 1, got 0")
+	}
+
+	if err := line.Validate(); err != nil {
+		t.Errorf("line.Validate() after BalancePortfolio = nil, got %s", err)
+	}
+}
@@ -0,0 +1,122 @@
+package alb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PrecedenceError describes the problems ValidatePrecedence found in a
+// line's precedence graph.
+type PrecedenceError struct {
+	// SelfLoops are the tasks that are their own predecessor.
+	SelfLoops []int
+
+	// Unknown are the predecessors that are not tasks on the line, as
+	// [task, predecessor] id pairs.
+	Unknown [][2]int
+
+	// Cycles are the cycles found, each listed in precedence order and
+	// ending with its first task again, e.g. [1 2 3 1].
+	Cycles [][]int
+}
+
+func (e *PrecedenceError) Error() string {
+	var problems []string
+	for _, id := range e.SelfLoops {
+		problems = append(problems, fmt.Sprintf("task %d is its own predecessor", id))
+	}
+
+	for _, pair := range e.Unknown {
+		problems = append(problems, fmt.Sprintf("task %d has predecessor %d not on the line", pair[0], pair[1]))
+	}
+
+	for _, cycle := range e.Cycles {
+		ids := make([]string, len(cycle))
+		for i, id := range cycle {
+			ids[i] = fmt.Sprint(id)
+		}
+		problems = append(problems, fmt.Sprintf("cycle %s", strings.Join(ids, " -> ")))
+	}
+	return fmt.Sprintf("precedence: %s", strings.Join(problems, "; "))
+}
+
+// ValidatePrecedence checks that the line's precedence graph is acyclic and
+// only refers to tasks on the line. It returns a *PrecedenceError listing
+// every self-loop and unknown predecessor, and a cycle through each task
+// that is on one, or nil if the graph is valid.
+//
+// The balance methods assume a valid precedence graph: tasks on a cycle can
+// never be assigned, and some methods do not terminate.
+func (l *Line) ValidatePrecedence() error {
+	e := &PrecedenceError{}
+	for _, task := range l.Tasks() {
+		for _, pred := range task.Preds() {
+			switch {
+			case pred == task:
+				e.SelfLoops = append(e.SelfLoops, task.ID)
+			case l.Task(pred.ID) != pred:
+				e.Unknown = append(e.Unknown, [2]int{task.ID, pred.ID})
+			}
+		}
+	}
+
+	// Depth first search along predecessors, where reaching a task still on
+	// the stack closes a cycle.
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+	state := make(map[int]int)
+	var stack []*Task
+
+	var visit func(task *Task)
+	visit = func(task *Task) {
+		state[task.ID] = onStack
+		stack = append(stack, task)
+
+		for _, pred := range task.Preds() {
+			if pred == task || l.Task(pred.ID) != pred {
+				continue
+			}
+
+			switch state[pred.ID] {
+			case unvisited:
+				visit(pred)
+			case onStack:
+				e.Cycles = append(e.Cycles, cyclePath(stack, pred))
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[task.ID] = done
+	}
+
+	for _, task := range l.Tasks() {
+		if state[task.ID] == unvisited {
+			visit(task)
+		}
+	}
+
+	if len(e.SelfLoops) == 0 && len(e.Unknown) == 0 && len(e.Cycles) == 0 {
+		return nil
+	}
+	return e
+}
+
+// cyclePath returns the cycle closed by reaching pred from the top of the
+// search stack, in precedence order: pred, then the tasks on the stack from
+// the top down to pred, each the predecessor of the one below it, then pred
+// again.
+func cyclePath(stack []*Task, pred *Task) []int {
+	i := len(stack) - 1
+	for stack[i] != pred {
+		i--
+	}
+
+	path := []int{pred.ID}
+	for j := len(stack) - 1; j > i; j-- {
+		path = append(path, stack[j].ID)
+	}
+	return append(path, pred.ID)
+}
@@ -0,0 +1,54 @@
+package alb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidatePrecedence(t *testing.T) {
+	line := newTestLine("TestValidatePrecedence", []float64{1, 1, 1, 1, 1}, [][2]int{{1, 2}, {2, 3}, {3, 4}}, 10)
+	if err := line.ValidatePrecedence(); err != nil {
+		t.Errorf("line.ValidatePrecedence() = nil, got %s", err)
+	}
+
+	// 4 -> 2 closes the cycle 2 -> 3 -> 4 -> 2.
+	line.Task(2).AddPred(line.Task(4))
+	line.Task(5).AddPred(line.Task(5))
+	line.Task(1).AddPred(NewTask(9, 1))
+
+	err := line.ValidatePrecedence()
+	perr, ok := err.(*PrecedenceError)
+	if !ok {
+		t.Fatalf("line.ValidatePrecedence() = *PrecedenceError, got %v", err)
+	}
+
+	if want := []int{5}; !reflect.DeepEqual(perr.SelfLoops, want) {
+		t.Errorf("SelfLoops = %v, got %v", want, perr.SelfLoops)
+	}
+
+	if want := [][2]int{{1, 9}}; !reflect.DeepEqual(perr.Unknown, want) {
+		t.Errorf("Unknown = %v, got %v", want, perr.Unknown)
+	}
+
+	if want := [][]int{{2, 3, 4, 2}}; !reflect.DeepEqual(perr.Cycles, want) {
+		t.Errorf("Cycles = %v, got %v", want, perr.Cycles)
+	}
+
+	want := "precedence: task 5 is its own predecessor; task 1 has predecessor 9 not on the line; cycle 2 -> 3 -> 4 -> 2"
+	if err.Error() != want {
+		t.Errorf("err.Error() = %q, got %q", want, err.Error())
+	}
+}
+
+func TestShortestStationTimeCycleTerminates(t *testing.T) {
+	line := newTestLine("TestShortestStationTimeCycleTerminates", []float64{1, 1, 1}, [][2]int{{1, 2}, {2, 1}}, 10)
+
+	err := line.BalanceByShortestStationTime(LongestTaskTime)
+	if err != nil {
+		t.Fatalf("BalanceByShortestStationTime returned an error, %s", err)
+	}
+
+	if got := line.NFreeTasks(); got != 2 {
+		t.Errorf("line.NFreeTasks() = 2, got %d", got)
+	}
+}
@@ -0,0 +1,98 @@
+package alb
+
+// Priorities maps task ids to priority values, where tasks with higher
+// values are preferred.
+type Priorities map[int]float64
+
+// PriorityRule computes the priority of every task on the line. Unlike a
+// Heuristic, which only sees the candidates it is choosing between, a rule
+// is computed once from the line's full precedence graph.
+type PriorityRule func(*Line) Priorities
+
+// PriorityRules are the priority rules known by name, e.g. to the balance
+// command and BalancePortfolio.
+var PriorityRules = map[string]PriorityRule{
+	"RankedPositionalWeight": RankedPositionalWeight,
+	"ImmediateFollowers":     ImmediateFollowers,
+	"TotalFollowers":         TotalFollowers,
+	"LatestStation":          LatestStation,
+	"TaskTime":               TaskTime,
+}
+
+// Heuristic returns a heuristic that picks the candidate with the highest
+// priority, breaking ties by the lowest task id.
+func (p Priorities) Heuristic() Heuristic {
+	return func(tasks []*Task) *Task {
+		var best *Task
+		for _, task := range tasks {
+			if best == nil || p[task.ID] > p[best.ID] ||
+				(p[task.ID] == p[best.ID] && task.ID < best.ID) {
+				best = task
+			}
+		}
+		return best
+	}
+}
+
+// RankedPositionalWeight prioritizes tasks by their positional weight: the
+// task's time plus the time of all of its direct and indirect successors.
+func RankedPositionalWeight(line *Line) Priorities {
+	g := newPrecedenceGraph(line.Tasks())
+
+	p := make(Priorities)
+	for _, task := range g.Tasks() {
+		p[task.ID] = task.Time()
+		for _, succ := range g.AllSuccs(task.ID) {
+			p[task.ID] += succ.Time()
+		}
+	}
+	return p
+}
+
+// ImmediateFollowers prioritizes tasks by their number of direct successors.
+func ImmediateFollowers(line *Line) Priorities {
+	g := newPrecedenceGraph(line.Tasks())
+
+	p := make(Priorities)
+	for _, task := range g.Tasks() {
+		p[task.ID] = float64(len(g.Succs(task.ID)))
+	}
+	return p
+}
+
+// TotalFollowers prioritizes tasks by their number of direct and indirect
+// successors.
+func TotalFollowers(line *Line) Priorities {
+	g := newPrecedenceGraph(line.Tasks())
+
+	p := make(Priorities)
+	for _, task := range g.Tasks() {
+		p[task.ID] = float64(len(g.AllSuccs(task.ID)))
+	}
+	return p
+}
+
+// LatestStation prioritizes tasks with the earliest latest station, i.e.
+// the tasks followed by the most stations' worth of work at the line's cycle
+// time. Without a cycle time it falls back to RankedPositionalWeight.
+func LatestStation(line *Line) Priorities {
+	p := RankedPositionalWeight(line)
+	time, ok := line.CycleTime()
+	if !ok || time <= 0 {
+		return p
+	}
+
+	for id, work := range p {
+		p[id] = float64(stations(work, time))
+	}
+	return p
+}
+
+// TaskTime prioritizes tasks with the longest task time.
+func TaskTime(line *Line) Priorities {
+	p := make(Priorities)
+	for _, task := range line.Tasks() {
+		p[task.ID] = task.Time()
+	}
+	return p
+}
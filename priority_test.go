@@ -0,0 +1,59 @@
+package alb
+
+import "testing"
+
+// newPriorityLine returns a line where task 1 precedes 2 and 3, and task 3
+// precedes 4. Task 5 is independent and the longest.
+func newPriorityLine() *Line {
+	preds := [][2]int{{1, 2}, {1, 3}, {3, 4}}
+	return newTestLine("newPriorityLine", []float64{2, 3, 1, 4, 6}, preds, 6)
+}
+
+func TestPriorityRules(t *testing.T) {
+	line := newPriorityLine()
+
+	var tests = []struct {
+		name string
+		rule PriorityRule
+		want Priorities
+	}{
+		{"RankedPositionalWeight", RankedPositionalWeight, Priorities{1: 10, 2: 3, 3: 5, 4: 4, 5: 6}},
+		{"ImmediateFollowers", ImmediateFollowers, Priorities{1: 2, 2: 0, 3: 1, 4: 0, 5: 0}},
+		{"TotalFollowers", TotalFollowers, Priorities{1: 3, 2: 0, 3: 1, 4: 0, 5: 0}},
+		{"LatestStation", LatestStation, Priorities{1: 2, 2: 1, 3: 1, 4: 1, 5: 1}},
+		{"TaskTime", TaskTime, Priorities{1: 2, 2: 3, 3: 1, 4: 4, 5: 6}},
+	}
+
+	for _, test := range tests {
+		got := test.rule(line)
+		if len(got) != len(test.want) {
+			t.Errorf("%s() = %v, got %v", test.name, test.want, got)
+			continue
+		}
+
+		for id, want := range test.want {
+			if got[id] != want {
+				t.Errorf("%s()[%d] = %.1f, got %.1f", test.name, id, want, got[id])
+			}
+		}
+	}
+}
+
+func TestPriorityHeuristic(t *testing.T) {
+	line := newPriorityLine()
+	h := RankedPositionalWeight(line).Heuristic()
+
+	// Task 1 outweighs task 5 by its successors, even though task 5 is
+	// longer and none of task 1's successors are candidates.
+	candidates := []*Task{line.Task(1), line.Task(5)}
+	if got := h(candidates); got != line.Task(1) {
+		t.Errorf("RankedPositionalWeight().Heuristic() = 1, got %d", got.ID)
+	}
+
+	// Ties are broken by the lowest task id.
+	h = ImmediateFollowers(line).Heuristic()
+	candidates = []*Task{line.Task(5), line.Task(4), line.Task(2)}
+	if got := h(candidates); got != line.Task(2) {
+		t.Errorf("ImmediateFollowers().Heuristic() = 2, got %d", got.ID)
+	}
+}
@@ -0,0 +1,234 @@
+package alb
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Report is the result of balancing a line in a form other tools can read:
+// the measurements, station loads, free tasks and task vector that the
+// Print functions print, and the parameters of the run.
+type Report struct {
+	Name         string        `json:"name"`
+	Measurements Measurements  `json:"measurements"`
+	Stations     []StationLoad `json:"stations"`
+	FreeTasks    []int         `json:"free_tasks"`
+
+	// TaskVector is the station of each assigned task, in order by task id.
+	TaskVector []int `json:"task_vector"`
+
+	// Params holds the settings of the run, e.g. the heuristic and method,
+	// which the caller fills in.
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// Measurements are the measures of a balance printed by PrintMeasurements.
+// LineEfficiency is a percentage, and 0 if no station is active. BoundGap is
+// NoGap if tasks are left free; it is then printed as "n/a" and left empty
+// in CSV.
+type Measurements struct {
+	CycleTime       float64 `json:"cycle_time"`
+	TheoreticalMin  int     `json:"theoretical_min"`
+	MeasuredMin     int     `json:"measured_min"`
+	LowerBound      int     `json:"lower_bound"`
+	BoundGap        int     `json:"bound_gap"`
+	LineEfficiency  float64 `json:"line_efficiency"`
+	SmoothnessIndex float64 `json:"smoothness_index"`
+}
+
+// StationLoad is the time and tasks of an active station.
+type StationLoad struct {
+	ID    int     `json:"id"`
+	Time  float64 `json:"time"`
+	Tasks []int   `json:"tasks"`
+}
+
+// NewReport returns a report of the line's balance at the given cycle time.
+func NewReport(line *Line, time float64) *Report {
+	return &Report{
+		Name:         line.Name,
+		Measurements: measure(line, time),
+		Stations:     stationLoads(line),
+		FreeTasks:    taskIDs(line.FreeTasks()),
+		TaskVector:   taskVector(line),
+		Params:       make(map[string]string),
+	}
+}
+
+func measure(line *Line, time float64) Measurements {
+	bounds := ComputeLowerBounds(line, time)
+	var efficiency float64
+	if line.NActiveStations() > 0 {
+		efficiency = Efficiency(line, time)
+	}
+
+	return Measurements{
+		CycleTime:       time,
+		TheoreticalMin:  int(math.Ceil(line.TaskTime() / time)),
+		MeasuredMin:     line.NActiveStations(),
+		LowerBound:      bounds.Best(),
+		BoundGap:        bounds.Gap(line),
+		LineEfficiency:  efficiency,
+		SmoothnessIndex: SmoothnessIndex(line, time),
+	}
+}
+
+func stationLoads(line *Line) []StationLoad {
+	loads := make([]StationLoad, 0)
+	for _, station := range line.Stations() {
+		if station.Active() {
+			loads = append(loads, StationLoad{
+				ID:    station.ID,
+				Time:  station.Time(),
+				Tasks: taskIDs(station.Tasks()),
+			})
+		}
+	}
+	return loads
+}
+
+func taskVector(line *Line) []int {
+	vector := make([]int, 0)
+	for _, task := range line.AssignedTasks() {
+		vector = append(vector, task.Assignment().ID)
+	}
+	return vector
+}
+
+func taskIDs(tasks []*Task) []int {
+	ids := make([]int, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteCSV writes the report as a header and a row per station load, so
+// the reports of several runs can be collected into one table. Each row
+// holds the name, the parameters in order by key, the measurements, the
+// free tasks and task vector as space separated lists, and the station's
+// id, time and tasks. A report without stations has a single row with the
+// station columns empty.
+func (r *Report) WriteCSV(w io.Writer) error {
+	keys := make([]string, 0, len(r.Params))
+	for k := range r.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	m := r.Measurements
+	header := []string{"name"}
+	run := []string{r.Name}
+	for _, k := range keys {
+		header = append(header, k)
+		run = append(run, r.Params[k])
+	}
+
+	header = append(header, "cycle_time", "theoretical_min", "measured_min", "lower_bound",
+		"bound_gap", "line_efficiency", "smoothness_index", "free_tasks", "task_vector",
+		"station", "station_time", "station_tasks")
+
+	run = append(run,
+		formatFloat(m.CycleTime),
+		strconv.Itoa(m.TheoreticalMin),
+		strconv.Itoa(m.MeasuredMin),
+		strconv.Itoa(m.LowerBound),
+		formatGap(m.BoundGap, ""),
+		formatFloat(m.LineEfficiency),
+		formatFloat(m.SmoothnessIndex),
+		strings.TrimSpace(idList(r.FreeTasks)),
+		strings.TrimSpace(idList(r.TaskVector)),
+	)
+
+	cw := csv.NewWriter(w)
+	cw.Write(header)
+	if len(r.Stations) == 0 {
+		cw.Write(append(run, "", "", ""))
+	}
+
+	for _, load := range r.Stations {
+		row := append(append([]string(nil), run...),
+			strconv.Itoa(load.ID),
+			formatFloat(load.Time),
+			strings.TrimSpace(idList(load.Tasks)),
+		)
+		cw.Write(row)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteText writes the report as PrintMeasurements, PrintFreeTasks,
+// PrintStations and PrintTaskVector print it. The parameters are not
+// written.
+func (r *Report) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	r.writeMeasurements(bw)
+	r.writeFreeTasks(bw)
+	r.writeStations(bw)
+	r.writeTaskVector(bw)
+	return bw.Flush()
+}
+
+func (r *Report) writeMeasurements(w io.Writer) {
+	m := r.Measurements
+	fmt.Fprintf(w, "%s\n", r.Name)
+	fmt.Fprintf(w, "cycle_time=%.2f\n", m.CycleTime)
+	fmt.Fprintf(w, "theoretical_min=%d\n", m.TheoreticalMin)
+	fmt.Fprintf(w, "measured_min=%d\n", m.MeasuredMin)
+	fmt.Fprintf(w, "lower_bound=%d\n", m.LowerBound)
+	fmt.Fprintf(w, "bound_gap=%s\n", formatGap(m.BoundGap, "n/a"))
+	fmt.Fprintf(w, "line_efficiency=%.1f%%\n", m.LineEfficiency)
+	fmt.Fprintf(w, "smoothness_index=%.1f\n", m.SmoothnessIndex)
+}
+
+func (r *Report) writeFreeTasks(w io.Writer) {
+	fmt.Fprintf(w, "free_tasks=%s\n", idList(r.FreeTasks))
+}
+
+func (r *Report) writeStations(w io.Writer) {
+	for _, load := range r.Stations {
+		fmt.Fprintf(w, "Station %d:\tTaskTime %.2f\tTasks %s\n", load.ID, load.Time, idList(load.Tasks))
+	}
+}
+
+func (r *Report) writeTaskVector(w io.Writer) {
+	fmt.Fprintf(w, "%s\n", idList(r.TaskVector))
+}
+
+// idList formats the numbers each followed by a space, as the Print
+// functions have always written lists.
+func idList(ids []int) string {
+	var s string
+	for _, id := range ids {
+		s += strconv.Itoa(id) + " "
+	}
+	return s
+}
+
+// formatGap formats a bound gap, or returns none for NoGap.
+func formatGap(gap int, none string) string {
+	if gap == NoGap {
+		return none
+	}
+	return strconv.Itoa(gap)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
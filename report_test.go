@@ -0,0 +1,152 @@
+package alb
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func newTestReport(t *testing.T) *Report {
+	line := newTestLine("TestReport", []float64{5, 4, 3, 3}, [][2]int{{1, 2}, {1, 3}}, 10)
+	err := line.BalanceByStationId(LongestTaskTime)
+	if err != nil {
+		t.Fatalf("BalanceByStationId returned an error, %s", err)
+	}
+
+	report := NewReport(line, 10)
+	report.Params["heuristic"] = "LongestTaskTime"
+	return report
+}
+
+func TestNewReport(t *testing.T) {
+	report := newTestReport(t)
+
+	m := report.Measurements
+	if m.CycleTime != 10 || m.TheoreticalMin != 2 || m.MeasuredMin != 2 || m.LineEfficiency != 75 {
+		t.Errorf("report.Measurements = 10 2 2 75%%, got %+v", m)
+	}
+
+	if len(report.Stations) != 2 || report.Stations[0].Time != 9 || len(report.Stations[0].Tasks) != 2 {
+		t.Errorf("report.Stations = [9 with 2 tasks, 6], got %+v", report.Stations)
+	}
+
+	want := []int{1, 1, 2, 2}
+	if len(report.TaskVector) != len(want) {
+		t.Fatalf("report.TaskVector = %v, got %v", want, report.TaskVector)
+	}
+	for i, id := range want {
+		if report.TaskVector[i] != id {
+			t.Errorf("report.TaskVector = %v, got %v", want, report.TaskVector)
+			break
+		}
+	}
+
+	if report.FreeTasks == nil || len(report.FreeTasks) != 0 {
+		t.Errorf("report.FreeTasks = [], got %v", report.FreeTasks)
+	}
+}
+
+func TestReportWriters(t *testing.T) {
+	report := newTestReport(t)
+
+	var buf bytes.Buffer
+	err := report.WriteText(&buf)
+	if err != nil {
+		t.Fatalf("WriteText returned an error, %s", err)
+	}
+
+	want := "TestReport\n" +
+		"cycle_time=10.00\n" +
+		"theoretical_min=2\n" +
+		"measured_min=2\n" +
+		"lower_bound=2\n" +
+		"bound_gap=0\n" +
+		"line_efficiency=75.0%\n" +
+		"smoothness_index=4.1\n" +
+		"free_tasks=\n" +
+		"Station 1:\tTaskTime 9.00\tTasks 1 2 \n" +
+		"Station 2:\tTaskTime 6.00\tTasks 3 4 \n" +
+		"1 1 2 2 \n"
+	if buf.String() != want {
+		t.Errorf("WriteText() = %q, got %q", want, buf.String())
+	}
+
+	buf.Reset()
+	err = report.WriteJSON(&buf)
+	if err != nil {
+		t.Fatalf("WriteJSON returned an error, %s", err)
+	}
+
+	var again Report
+	err = json.Unmarshal(buf.Bytes(), &again)
+	if err != nil {
+		t.Fatalf("json.Unmarshal(WriteJSON()) returned an error, %s", err)
+	}
+	if again.Measurements != report.Measurements || again.Params["heuristic"] != "LongestTaskTime" {
+		t.Errorf("json report = %+v, got %+v", report, again)
+	}
+
+	buf.Reset()
+	err = report.WriteCSV(&buf)
+	if err != nil {
+		t.Fatalf("WriteCSV returned an error, %s", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(records) != 3 {
+		t.Fatalf("WriteCSV() = 3 records, got %v %v", records, err)
+	}
+
+	rows := make([]map[string]string, 2)
+	for i := range rows {
+		rows[i] = make(map[string]string)
+		for j, key := range records[0] {
+			rows[i][key] = records[i+1][j]
+		}
+	}
+
+	got := rows[1]
+	if got["heuristic"] != "LongestTaskTime" || got["measured_min"] != "2" || got["task_vector"] != "1 1 2 2" ||
+		got["station"] != "2" || got["station_time"] != "6" || got["station_tasks"] != "3 4" {
+		t.Errorf("WriteCSV() row 2 = LongestTaskTime 2 \"1 1 2 2\" station 2 time 6 tasks \"3 4\", got %v", got)
+	}
+
+	if rows[0]["station_tasks"] != "1 2" {
+		t.Errorf("WriteCSV() row 1 station_tasks = \"1 2\", got %q", rows[0]["station_tasks"])
+	}
+}
+
+func TestReportEmptyBalance(t *testing.T) {
+	line := newTestLine("TestReportEmptyBalance", []float64{5, 4, 3}, nil, 10)
+	report := NewReport(line, 10)
+
+	m := report.Measurements
+	if m.LineEfficiency != 0 || m.BoundGap != NoGap || len(report.FreeTasks) != 3 {
+		t.Errorf("report = efficiency 0, no gap and 3 free tasks, got %+v", report)
+	}
+
+	var text bytes.Buffer
+	_ = report.WriteText(&text)
+	if !strings.Contains(text.String(), "bound_gap=n/a\n") {
+		t.Errorf("WriteText() = bound_gap=n/a, got %q", text.String())
+	}
+
+	var buf bytes.Buffer
+	err := report.WriteJSON(&buf)
+	if err != nil {
+		t.Errorf("WriteJSON returned an error, %s", err)
+	}
+
+	buf.Reset()
+	err = report.WriteCSV(&buf)
+	if err != nil {
+		t.Fatalf("WriteCSV returned an error, %s", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(records) != 2 {
+		t.Errorf("WriteCSV() = 2 records, got %v %v", records, err)
+	}
+}
@@ -0,0 +1,172 @@
+package alb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// Unassigned is the station id a SolutionChange reports for a task that a
+// solution leaves free.
+const Unassigned = -1
+
+// Solution is a balance of a line kept apart from the line itself: the
+// station each task is assigned to, keyed by task id, and the cycle time it
+// was balanced at. Tasks left free are not in Assignments.
+//
+// A solution shares nothing with the lines it is extracted from or applied
+// to, so rebalancing them leaves it unchanged. Extract one from a line with
+// Line.Solution, store it with Write and ReadSolution, and assign it to a
+// line with Line.Apply.
+type Solution struct {
+	CycleTime   float64           `json:"cycle_time"`
+	Assignments map[int]int       `json:"assignments"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+}
+
+// Solution returns the line's current balance. The cycle time is the line's
+// cycle time, or its longest station time if it has no cycle time, and the
+// line's name is recorded in the metadata.
+func (l *Line) Solution() *Solution {
+	s := &Solution{
+		CycleTime:   l.measuredCycleTime(),
+		Assignments: make(map[int]int),
+		Metadata:    map[string]string{"line": l.Name},
+	}
+
+	for _, task := range l.tasks {
+		if task.IsAssigned() {
+			s.Assignments[task.ID] = task.Assignment().ID
+		}
+	}
+	return s
+}
+
+// solution returns the line's current balance, recording the method that
+// produced it in the metadata along with any further key and value pairs.
+func (l *Line) solution(method string, metadata ...string) *Solution {
+	s := l.Solution()
+	s.Metadata["method"] = method
+	for i := 0; i+1 < len(metadata); i += 2 {
+		s.Metadata[metadata[i]] = metadata[i+1]
+	}
+	return s
+}
+
+// Apply unassigns all tasks on the line and assigns them to the stations
+// given by the solution, in order by task id, activating exactly the
+// stations that are assigned tasks. The line's constraints are neither
+// checked nor changed.
+func (l *Line) Apply(s *Solution) error {
+	a := make(map[int][]int)
+	for _, taskID := range s.tasks() {
+		a[s.Assignments[taskID]] = append(a[s.Assignments[taskID]], taskID)
+	}
+	return l.Restore(newSnapshot(a))
+}
+
+// Stations returns the number of stations the solution assigns tasks to.
+func (s *Solution) Stations() int {
+	stations := make(map[int]bool)
+	for _, stationID := range s.Assignments {
+		stations[stationID] = true
+	}
+	return len(stations)
+}
+
+// Equal reports whether two solutions have the same cycle time and assign
+// every task to the same station. Metadata is ignored.
+func (s *Solution) Equal(o *Solution) bool {
+	if math.Abs(s.CycleTime-o.CycleTime) > eps || len(s.Assignments) != len(o.Assignments) {
+		return false
+	}
+
+	for taskID, stationID := range s.Assignments {
+		other, ok := o.Assignments[taskID]
+		if !ok || other != stationID {
+			return false
+		}
+	}
+	return true
+}
+
+// SolutionChange is a task assigned to different stations by two solutions.
+type SolutionChange struct {
+	Task int
+	From int
+	To   int
+}
+
+func (c SolutionChange) String() string {
+	return fmt.Sprintf("task %d: %s -> %s", c.Task, stationName(c.From), stationName(c.To))
+}
+
+func stationName(id int) string {
+	if id == Unassigned {
+		return "free"
+	}
+	return fmt.Sprintf("station %d", id)
+}
+
+// Diff returns the tasks whose assignment changes from s to o, sorted by
+// task id. Tasks free in one solution have the Unassigned station there.
+func (s *Solution) Diff(o *Solution) []SolutionChange {
+	station := func(sol *Solution, taskID int) int {
+		if stationID, ok := sol.Assignments[taskID]; ok {
+			return stationID
+		}
+		return Unassigned
+	}
+
+	ids := make(map[int]bool)
+	for taskID := range s.Assignments {
+		ids[taskID] = true
+	}
+	for taskID := range o.Assignments {
+		ids[taskID] = true
+	}
+
+	var changes []SolutionChange
+	for taskID := range ids {
+		from, to := station(s, taskID), station(o, taskID)
+		if from != to {
+			changes = append(changes, SolutionChange{Task: taskID, From: from, To: to})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Task < changes[j].Task })
+	return changes
+}
+
+// tasks returns the ids of the solution's assigned tasks in order.
+func (s *Solution) tasks() []int {
+	ids := make([]int, 0, len(s.Assignments))
+	for taskID := range s.Assignments {
+		ids = append(ids, taskID)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Write writes the solution as indented JSON.
+func (s *Solution) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// ReadSolution reads a solution written by Write.
+func ReadSolution(r io.Reader) (*Solution, error) {
+	s := &Solution{}
+	err := json.NewDecoder(r).Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("solution: %s", err)
+	}
+
+	if s.Assignments == nil {
+		s.Assignments = make(map[int]int)
+	}
+	return s, nil
+}
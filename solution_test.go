@@ -0,0 +1,151 @@
+package alb
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSolutionApply(t *testing.T) {
+	line := newTestLine("TestSolutionApply", []float64{5, 4, 3, 3, 3, 2}, [][2]int{{1, 2}}, 10)
+	err := line.BalanceByStationId(LongestTaskTime)
+	if err != nil {
+		t.Fatalf("BalanceByStationId returned an error, %s", err)
+	}
+
+	sol := line.Solution()
+	if sol.CycleTime != 10 || sol.Stations() != line.NActiveStations() || len(sol.Assignments) != 6 {
+		t.Errorf("line.Solution() = cycle 10, %d stations, 6 tasks, got cycle %.2f, %d stations, %d tasks",
+			line.NActiveStations(), sol.CycleTime, sol.Stations(), len(sol.Assignments))
+	}
+
+	other := newTestLine("TestSolutionApply", []float64{5, 4, 3, 3, 3, 2}, [][2]int{{1, 2}}, 10)
+	err = other.Apply(sol)
+	if err != nil {
+		t.Fatalf("Apply returned an error, %s", err)
+	}
+
+	if got := other.Solution(); !got.Equal(sol) {
+		t.Errorf("Apply(sol).Solution() = %v, got %v", sol.Assignments, got.Assignments)
+	}
+
+	if other.NActiveStations() != line.NActiveStations() {
+		t.Errorf("Apply(sol).NActiveStations() = %d, got %d", line.NActiveStations(), other.NActiveStations())
+	}
+
+	err = other.UnassignTasks()
+	if err != nil {
+		t.Fatalf("UnassignTasks returned an error, %s", err)
+	}
+
+	if len(sol.Assignments) != 6 {
+		t.Errorf("len(sol.Assignments) after UnassignTasks = 6, got %d", len(sol.Assignments))
+	}
+
+	err = other.Apply(&Solution{Assignments: map[int]int{1: 99}})
+	if err == nil {
+		t.Errorf("Apply(unknown station) = error, got nil")
+	}
+}
+
+func TestSolutionApplyInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		assignments map[int]int
+	}{
+		{"unknown station", map[int]int{1: 1, 2: 99}},
+		{"unknown task", map[int]int{1: 1, 99: 2}},
+	}
+
+	for _, test := range tests {
+		line := newTestLine("TestSolutionApplyInvalid", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+		err := line.BalanceByStationId(LongestTaskTime)
+		if err != nil {
+			t.Fatalf("BalanceByStationId returned an error, %s", err)
+		}
+		want := line.Solution()
+
+		err = line.Apply(&Solution{Assignments: test.assignments})
+		if err == nil {
+			t.Errorf("Apply(%s) = error, got nil", test.name)
+		}
+
+		if got := line.Solution(); !got.Equal(want) || line.NActiveStations() != want.Stations() {
+			t.Errorf("line after Apply(%s) = %v, got %v", test.name, want.Assignments, got.Assignments)
+		}
+	}
+}
+
+func TestSolutionDiff(t *testing.T) {
+	a := &Solution{CycleTime: 10, Assignments: map[int]int{1: 1, 2: 1, 3: 2}}
+	b := &Solution{CycleTime: 10, Assignments: map[int]int{1: 1, 2: 2, 4: 2}}
+
+	want := []SolutionChange{
+		{Task: 2, From: 1, To: 2},
+		{Task: 3, From: 2, To: Unassigned},
+		{Task: 4, From: Unassigned, To: 2},
+	}
+
+	got := a.Diff(b)
+	if len(got) != len(want) {
+		t.Fatalf("a.Diff(b) = %v, got %v", want, got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("a.Diff(b)[%d] = %s, got %s", i, want[i], got[i])
+		}
+	}
+
+	if a.Equal(b) || !a.Equal(a) {
+		t.Errorf("a.Equal(b), a.Equal(a) = false true, got %t %t", a.Equal(b), a.Equal(a))
+	}
+
+	if len(a.Diff(a)) != 0 {
+		t.Errorf("a.Diff(a) = [], got %v", a.Diff(a))
+	}
+}
+
+func TestSolutionReadWrite(t *testing.T) {
+	sol := &Solution{
+		CycleTime:   27.5,
+		Assignments: map[int]int{1: 1, 2: 1, 3: 2},
+		Metadata:    map[string]string{"method": "station"},
+	}
+
+	var buf bytes.Buffer
+	err := sol.Write(&buf)
+	if err != nil {
+		t.Fatalf("Write returned an error, %s", err)
+	}
+
+	got, err := ReadSolution(&buf)
+	if err != nil {
+		t.Fatalf("ReadSolution returned an error, %s", err)
+	}
+
+	if !got.Equal(sol) || got.Metadata["method"] != "station" {
+		t.Errorf("ReadSolution(Write(sol)) = %v, got %v", sol, got)
+	}
+
+	_, err = ReadSolution(bytes.NewBufferString("{"))
+	if err == nil {
+		t.Errorf("ReadSolution(invalid) = error, got nil")
+	}
+}
+
+func TestBalancerSolution(t *testing.T) {
+	line := newTestLine("TestBalancerSolution", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+
+	result, err := line.BalanceByBranchAndBound(LongestTaskTime, BranchAndBoundLimits{})
+	if err != nil {
+		t.Fatalf("BalanceByBranchAndBound returned an error, %s", err)
+	}
+
+	if result.Solution == nil || !result.Solution.Equal(line.Solution()) {
+		t.Fatalf("result.Solution = line.Solution(), got %v", result.Solution)
+	}
+
+	if got := result.Solution.Stations(); got != result.Stations {
+		t.Errorf("result.Solution.Stations() = %d, got %d", result.Stations, got)
+	}
+}
@@ -82,6 +82,24 @@ func (s *Station) AssignTask(task *Task) error {
 	return nil
 }
 
+// insertTask assigns a task to the station at position i of its tasks.
+func (s *Station) insertTask(i int, task *Task) {
+	s.tasks = append(s.tasks, nil)
+	copy(s.tasks[i+1:], s.tasks[i:])
+	s.tasks[i] = task
+	task.Assign(s)
+}
+
+// index returns the position of a task in the station's tasks, or -1.
+func (s *Station) index(id int) int {
+	for i, task := range s.tasks {
+		if task.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
 // WithdrawTask removes a task from the station.
 func (s *Station) WithdrawTask(id int) error {
 	tasks := s.tasks[:0]
@@ -97,6 +115,7 @@ func (s *Station) WithdrawTask(id int) error {
 		}
 	}
 
+	s.tasks = tasks
 	return nil
 }
 
@@ -1,6 +1,9 @@
 package alb
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestIsActive(t *testing.T) {
 	station1 := NewStation(1)
@@ -38,7 +41,35 @@ func TestIsActive(t *testing.T) {
 //
 //}
 
-// TODO(ah): test unassigning task in station
-//func TestUnassignTaskInStation(t *testing.T) {
-//
-//}
+func TestUnassignTaskInStation(t *testing.T) {
+	var tests = []struct {
+		id    int
+		tasks []int
+		time  float64
+	}{
+		{1, []int{2, 3}, 5},
+		{2, []int{1, 3}, 4},
+		{3, []int{1, 2}, 3},
+	}
+
+	for _, test := range tests {
+		station := NewStation(1)
+		for i, time := range []float64{1, 2, 3} {
+			_ = station.AssignTask(NewTask(i+1, time))
+		}
+
+		err := station.WithdrawTask(test.id)
+		if err != nil {
+			t.Fatalf("station.WithdrawTask(%d) returned an error, %s", test.id, err)
+		}
+
+		var got []int
+		for _, task := range station.Tasks() {
+			got = append(got, task.ID)
+		}
+		if !reflect.DeepEqual(got, test.tasks) || station.Time() != test.time {
+			t.Errorf("station.WithdrawTask(%d) = tasks %v time %.2f, got tasks %v time %.2f",
+				test.id, test.tasks, test.time, got, station.Time())
+		}
+	}
+}
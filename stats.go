@@ -3,6 +3,8 @@ package alb
 import (
 	"fmt"
 	"math"
+	"os"
+	"text/tabwriter"
 )
 
 func Efficiency(line *Line, time float64) float64 {
@@ -23,34 +25,63 @@ func SmoothnessIndex(line *Line, time float64) float64 {
 }
 
 func PrintMeasurements(line *Line, time float64) {
-	fmt.Printf("%s\n", line.Name)
-	fmt.Printf("cycle_time=%.2f\n", time)
-	fmt.Printf("theoretical_min=%d\n", int(math.Ceil(line.TaskTime()/time)))
-	fmt.Printf("measured_min=%d\n", line.NActiveStations())
-	fmt.Printf("line_efficiency=%.1f%%\n", Efficiency(line, time))
-	fmt.Printf("smoothness_index=%.1f\n", SmoothnessIndex(line, time))
+	r := &Report{Name: line.Name, Measurements: measure(line, time)}
+	r.writeMeasurements(os.Stdout)
 }
 
-func PrintStations(line *Line) {
-	for _, station := range line.Stations() {
-		if station.Active() {
-			fmt.Println(station)
-		}
+func PrintPrecedenceGraph(g *PrecedenceGraph) {
+	var arcs string
+	for _, arc := range g.RedundantArcs() {
+		arcs += fmt.Sprintf("%d,%d ", arc[0], arc[1])
 	}
+
+	fmt.Printf("order_strength=%.3f\n", g.OrderStrength())
+	fmt.Printf("west_ratio=%.2f\n", g.WestRatio())
+	fmt.Printf("time_variability=%.2f\n", g.TimeVariability())
+	fmt.Printf("depth=%d\n", g.Depth())
+	fmt.Printf("redundant_arcs=%s\n", arcs)
+}
+
+func PrintStations(line *Line) {
+	r := &Report{Stations: stationLoads(line)}
+	r.writeStations(os.Stdout)
 }
 
 func PrintFreeTasks(line *Line) {
-	var tasks string
-	for _, task := range line.FreeTasks() {
-		tasks += fmt.Sprintf("%d ", task.ID)
-	}
-	fmt.Printf("free_tasks=%s\n", tasks)
+	r := &Report{FreeTasks: taskIDs(line.FreeTasks())}
+	r.writeFreeTasks(os.Stdout)
 }
 
 func PrintTaskVector(line *Line) {
-	var tasks string
-	for _, task := range line.AssignedTasks() {
-		tasks += fmt.Sprintf("%d ", task.Assignment().ID)
+	r := &Report{TaskVector: taskVector(line)}
+	r.writeTaskVector(os.Stdout)
+}
+
+// PrintPortfolio prints a summary table of a portfolio's runs, marking the
+// best run with an asterisk and failed runs with "error", followed by the
+// errors of the failed runs.
+func PrintPortfolio(result *PortfolioResult) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "heuristic\tmethod\tstations\tfree_tasks\tsmoothness_index\telapsed\t\n")
+	var failed []*PortfolioRun
+	for i := range result.Runs {
+		run := &result.Runs[i]
+		mark := ""
+		if run == result.Best {
+			mark = "*"
+		}
+
+		if run.Err != nil {
+			failed = append(failed, run)
+			fmt.Fprintf(w, "%s\t%s\terror\t\t\t%s\t\n", run.Heuristic, run.Method, run.Elapsed)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.1f\t%s\t%s\n", run.Heuristic, run.Method,
+			run.Stations, run.FreeTasks, run.Smoothness, run.Elapsed, mark)
+	}
+	w.Flush()
+
+	for _, run := range failed {
+		fmt.Printf("%s %s: %s\n", run.Heuristic, run.Method, run.Err)
 	}
-	fmt.Printf("%s\n", tasks)
 }
@@ -0,0 +1,224 @@
+package alb
+
+import (
+	"math/rand"
+	"time"
+)
+
+// TabuOptions configures BalanceByTabuSearch.
+type TabuOptions struct {
+	// Objective is the cost being minimized (default MinimizeStations).
+	Objective Objective
+
+	// Tenure is the number of iterations a task may not be moved back to a
+	// station it left (default 7).
+	Tenure int
+
+	// Neighbors, if non-zero, samples that many random moves each iteration
+	// instead of trying every shift and swap.
+	Neighbors int
+
+	// Iterations and TimeLimit stop the search. If both are zero, the
+	// search runs for 1000 iterations.
+	Iterations int
+	TimeLimit  time.Duration
+
+	// Seed seeds the sampled moves, so runs with the same seed and line
+	// produce the same balance.
+	Seed int64
+
+	// Heuristic builds the starting balance with BalanceByStationId if the
+	// line has free tasks. The default is LongestTaskTime.
+	Heuristic Heuristic
+}
+
+// TabuStep records one iteration of BalanceByTabuSearch.
+type TabuStep struct {
+	Iteration int
+	Move      Move
+	Cost      float64
+	Best      float64
+	Stations  int
+}
+
+// TabuResult describes the outcome of BalanceByTabuSearch.
+type TabuResult struct {
+	Stations   int
+	Smoothness float64
+	Cost       float64
+
+	Iterations    int
+	BestIteration int
+	Trace         []TabuStep
+
+	// Solution is the balance assigned to the line.
+	Solution *Solution
+
+	Elapsed time.Duration
+}
+
+// BalanceByTabuSearch improves a balance of the line by tabu search,
+// starting from its current assignment. Each iteration makes the lowest cost
+// shift or swap between active stations (see Improve), even if it raises
+// the cost, except that a task may not return to a station it recently left
+// unless doing so beats the best balance found (aspiration). The best
+// balance found is assigned to the line when it finishes.
+func (l *Line) BalanceByTabuSearch(opts TabuOptions) (*TabuResult, error) {
+	start := time.Now()
+	if opts.Objective == (Objective{}) {
+		opts.Objective = MinimizeStations
+	}
+	if opts.Tenure <= 0 {
+		opts.Tenure = 7
+	}
+	if opts.Iterations == 0 && opts.TimeLimit == 0 {
+		opts.Iterations = 1000
+	}
+	if opts.Heuristic == nil {
+		opts.Heuristic = LongestTaskTime
+	}
+
+	if l.NFreeTasks() > 0 {
+		err := l.reset()
+		if err != nil {
+			return nil, err
+		}
+
+		err = l.BalanceByStationId(opts.Heuristic)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	expired := func() bool {
+		return opts.TimeLimit > 0 && time.Since(start) >= opts.TimeLimit
+	}
+
+	// tabu holds the iteration until which a task may not be moved to a
+	// station, keyed by task and station id.
+	tabu := make(map[[2]int]int)
+	cost := opts.Objective.Cost(l)
+	best, bestCost := l.Snapshot(), cost
+	result := &TabuResult{}
+	for opts.Iterations == 0 || result.Iterations < opts.Iterations {
+		if expired() {
+			break
+		}
+		iteration := result.Iterations + 1
+
+		var chosen *Move
+		var chosenCost float64
+		for _, m := range l.neighborhood(rng, opts.Neighbors) {
+			if expired() {
+				break
+			}
+
+			ok, err := l.do(&m)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+
+			next := opts.Objective.Cost(l)
+			err = l.undo(&m)
+			if err != nil {
+				return nil, err
+			}
+
+			forbidden := tabu[[2]int{m.Task, m.To}] >= iteration
+			if m.Kind == Swap && tabu[[2]int{m.Other, m.From}] >= iteration {
+				forbidden = true
+			}
+			if forbidden && next >= bestCost-eps {
+				continue
+			}
+
+			if chosen == nil || next < chosenCost-eps {
+				m := m
+				chosen, chosenCost = &m, next
+			}
+		}
+
+		if chosen == nil {
+			break
+		}
+
+		ok, err := l.do(chosen)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		tabu[[2]int{chosen.Task, chosen.From}] = iteration + opts.Tenure
+		if chosen.Kind == Swap {
+			tabu[[2]int{chosen.Other, chosen.To}] = iteration + opts.Tenure
+		}
+
+		cost = chosenCost
+		result.Iterations = iteration
+		if cost < bestCost-eps {
+			best, bestCost = l.Snapshot(), cost
+			result.BestIteration = iteration
+		}
+
+		result.Trace = append(result.Trace, TabuStep{
+			Iteration: iteration,
+			Move:      *chosen,
+			Cost:      cost,
+			Best:      bestCost,
+			Stations:  l.NActiveStations(),
+		})
+	}
+
+	err := l.Restore(best)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Stations = l.NActiveStations()
+	result.Smoothness = l.smoothness()
+	result.Cost = bestCost
+	result.Solution = l.solution("tabu")
+	result.Elapsed = time.Since(start)
+	return result, nil
+}
+
+// neighborhood returns every shift and swap between the line's active
+// stations, or n random ones if n is non-zero.
+func (l *Line) neighborhood(rng *rand.Rand, n int) []Move {
+	var moves []Move
+	if n > 0 {
+		for i := 0; i < n; i++ {
+			m, ok := l.randomMove(rng)
+			if !ok {
+				break
+			}
+			moves = append(moves, m)
+		}
+		return moves
+	}
+
+	stations := l.ActiveStations()
+	tasks := l.AssignedTasks()
+	for _, task := range tasks {
+		for _, station := range stations {
+			if task.Assignment() != station {
+				moves = append(moves, Move{Kind: Shift, Task: task.ID, To: station.ID})
+			}
+		}
+	}
+
+	for i, task := range tasks {
+		for _, other := range tasks[i+1:] {
+			if task.Assignment() != other.Assignment() {
+				moves = append(moves, Move{Kind: Swap, Task: task.ID, Other: other.ID, To: other.Assignment().ID})
+			}
+		}
+	}
+	return moves
+}
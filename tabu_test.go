@@ -0,0 +1,90 @@
+package alb
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestTabuSearch(t *testing.T) {
+	line := newTestLine("TestTabuSearch", []float64{5, 4, 3, 3, 3, 2}, nil, 10)
+	err := line.Restore(newSnapshot(map[int][]int{1: {1}, 2: {2, 3}, 3: {4, 5}, 4: {6}}))
+	if err != nil {
+		t.Fatalf("line.Restore returned an error, %s", err)
+	}
+
+	result, err := line.BalanceByTabuSearch(TabuOptions{Iterations: 50})
+	if err != nil {
+		t.Fatalf("BalanceByTabuSearch returned an error, %s", err)
+	}
+
+	if result.Stations != 2 || line.NActiveStations() != 2 {
+		t.Errorf("BalanceByTabuSearch() = 2 stations, got %d (line %d)", result.Stations, line.NActiveStations())
+	}
+
+	if len(result.Trace) != result.Iterations {
+		t.Errorf("len(BalanceByTabuSearch().Trace) = %d, got %d", result.Iterations, len(result.Trace))
+	}
+
+	for i, step := range result.Trace {
+		if i > 0 && step.Best > result.Trace[i-1].Best {
+			t.Errorf("trace step %d best cost %.2f increased from %.2f", step.Iteration, step.Best, result.Trace[i-1].Best)
+		}
+	}
+
+	if got := line.NFreeTasks(); got != 0 {
+		t.Errorf("line.NFreeTasks() = 0, got %d", got)
+	}
+}
+
+func TestTabuSearchTimeLimit(t *testing.T) {
+	line := newTestLine("TestTabuSearchTimeLimit", []float64{5, 4, 3, 3, 3, 2, 6, 1}, [][2]int{{1, 3}, {3, 5}}, 10)
+
+	result, err := line.BalanceByTabuSearch(TabuOptions{Objective: MinimizeSmoothness, TimeLimit: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("BalanceByTabuSearch returned an error, %s", err)
+	}
+
+	if result.Iterations == 0 {
+		t.Error("BalanceByTabuSearch(TimeLimit: 20ms) ran no iterations")
+	}
+
+	if result.Cost != SmoothnessIndex(line, 10) {
+		t.Errorf("BalanceByTabuSearch().Cost = %.2f, got %.2f", SmoothnessIndex(line, 10), result.Cost)
+	}
+}
+
+func TestTabuSearchKeepsPrecedence(t *testing.T) {
+	file, err := os.Open("specs/buxey.in2")
+	if err != nil {
+		t.Fatalf("opening buxey.in2 returned an error, %s", err)
+	}
+	defer file.Close()
+
+	inst, err := ReadIn2(file)
+	if err != nil {
+		t.Fatalf("ReadIn2 returned an error, %s", err)
+	}
+
+	line, err := inst.Line()
+	if err != nil {
+		t.Fatalf("inst.Line() returned an error, %s", err)
+	}
+
+	// The balance command's default constraints, which do not check the
+	// station order of predecessors.
+	line.AddConstraints([]Constraint{
+		&SingleTaskAssignment{},
+		&RestrictedStationTime{Time: 33},
+		&PredecessorsStartToStart{},
+	})
+
+	_, err = line.BalanceByTabuSearch(TabuOptions{Seed: 1, Iterations: 200})
+	if err != nil {
+		t.Fatalf("BalanceByTabuSearch returned an error, %s", err)
+	}
+
+	if err := line.Validate(); err != nil {
+		t.Errorf("line.Validate() after BalanceByTabuSearch = nil, got %s", err)
+	}
+}
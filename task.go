@@ -8,9 +8,16 @@ import (
 
 // Task is a physical task performed at a station on the assembly line.
 type Task struct {
-	ID   int
-	time float64
+	ID int
+
+	// Name and Attributes describe the task, e.g. as read from a data file.
+	// They are not used in balancing.
+	Name       string
+	Attributes map[string]string
+
+	time         float64
 	predecessors map[int]*Task
+	successors   map[int]*Task
 	assignment   *Station
 }
 
@@ -20,6 +27,7 @@ func NewTask(id int, time float64) *Task {
 		ID:           id,
 		time:         time,
 		predecessors: make(map[int]*Task, 0),
+		successors:   make(map[int]*Task, 0),
 	}
 }
 
@@ -51,11 +59,54 @@ func (t *Task) Preds() []*Task {
 	return preds
 }
 
-// AddPred adds a task to the task's list of predecessors.
+// AddPred adds a task to the task's list of predecessors, and the task to
+// the predecessor's list of successors.
 func (t *Task) AddPred(task *Task) {
 	if t.Pred(task.ID) == nil {
 		t.predecessors[task.ID] = task
+		if task.successors == nil {
+			task.successors = make(map[int]*Task)
+		}
+		task.successors[t.ID] = t
+	}
+}
+
+// RemovePred removes a task from the task's list of predecessors, and the
+// task from the predecessor's list of successors.
+func (t *Task) RemovePred(id int) {
+	pred := t.Pred(id)
+	if pred == nil {
+		return
 	}
+
+	delete(t.predecessors, id)
+	if pred.successors[t.ID] == t {
+		delete(pred.successors, t.ID)
+	}
+}
+
+// Succ returns a task's successor by id.
+func (t *Task) Succ(id int) *Task {
+	task, _ := t.successors[id]
+	return task
+}
+
+// Succs returns an array of successor tasks, the tasks that have the task as
+// a predecessor, sorted by task ID.
+func (t *Task) Succs() []*Task {
+	var keys []int
+	for k := range t.successors {
+		keys = append(keys, k)
+	}
+
+	sort.Ints(keys)
+
+	var succs []*Task
+	for _, k := range keys {
+		succs = append(succs, t.successors[k])
+	}
+
+	return succs
 }
 
 // IsAssigned checks if the task has a current station assignment.
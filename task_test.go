@@ -135,3 +135,29 @@ func TestUnassignFromStation(t *testing.T) {
 		t.Errorf("task.Withdraw(%d) = error, got nil", station1.ID)
 	}
 }
+
+func TestSuccsInTask(t *testing.T) {
+	task1 := NewTask(1, 10.0)
+	task2 := NewTask(2, 10.0)
+	task3 := NewTask(3, 10.0)
+
+	task2.AddPred(task1)
+	task3.AddPred(task1)
+
+	if got := task1.Succs(); len(got) != 2 || got[0] != task2 || got[1] != task3 {
+		t.Errorf("task1.Succs() = [2 3], got %v", got)
+	}
+
+	if got := task1.Succ(3); got != task3 {
+		t.Errorf("task1.Succ(3) = %v, got %v", task3, got)
+	}
+
+	task3.RemovePred(1)
+	if task3.Pred(1) != nil || task1.Succ(3) != nil {
+		t.Errorf("task3.RemovePred(1) = unlinked, got pred %v succ %v", task3.Pred(1), task1.Succ(3))
+	}
+
+	if got := task1.Succs(); len(got) != 1 || got[0] != task2 {
+		t.Errorf("task1.Succs() after RemovePred = [2], got %v", got)
+	}
+}
@@ -0,0 +1,217 @@
+package alb
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ViolationKind classifies a Violation.
+type ViolationKind int
+
+const (
+	// UnassignedTask is a task not assigned to any station.
+	UnassignedTask ViolationKind = iota
+
+	// PrecedenceViolation is a task assigned while one of its predecessors
+	// is free or assigned to a later station.
+	PrecedenceViolation
+
+	// StationOverload is a station whose time exceeds the cycle time.
+	StationOverload
+
+	// ConstraintViolation is a task whose assignment fails one of the
+	// line's other constraints.
+	ConstraintViolation
+)
+
+func (k ViolationKind) String() string {
+	switch k {
+	case UnassignedTask:
+		return "unassigned"
+	case PrecedenceViolation:
+		return "precedence"
+	case StationOverload:
+		return "overload"
+	case ConstraintViolation:
+		return "constraint"
+	}
+	return fmt.Sprintf("ViolationKind(%d)", int(k))
+}
+
+// Violation is one way a balance fails to be valid.
+type Violation struct {
+	Kind ViolationKind
+
+	// Task is the task involved, or 0 for a StationOverload.
+	Task int
+
+	// Station is the station involved, or Unassigned for an
+	// UnassignedTask.
+	Station int
+
+	Message string
+}
+
+func (v Violation) Error() string {
+	return v.Message
+}
+
+// ValidationError is returned by Validate for a balance with violations.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Violations) == 1 {
+		return fmt.Sprintf("validate: %s", e.Violations[0])
+	}
+	return fmt.Sprintf("validate: %d violations, first: %s", len(e.Violations), e.Violations[0])
+}
+
+// Validate checks the line's current balance, returning a *ValidationError
+// listing every violation, or nil if there are none. See Violations.
+func (l *Line) Validate() error {
+	violations := l.Violations()
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: violations}
+}
+
+// ValidateSolution validates a solution against a copy of the line. If the
+// line has no cycle time, the solution's cycle time is enforced instead.
+// It returns an error other than a *ValidationError if the solution
+// assigns tasks or stations the line does not have.
+func (l *Line) ValidateSolution(s *Solution) error {
+	c, err := l.Clone()
+	if err != nil {
+		return err
+	}
+
+	err = c.Apply(s)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := c.CycleTime(); !ok && s.CycleTime > 0 {
+		c.AddConstraint(&RestrictedStationTime{Time: s.CycleTime})
+	}
+	return c.Validate()
+}
+
+// Violations returns every violation of the line's current balance: tasks
+// left unassigned, tasks assigned before their predecessors (whether or not
+// the line has precedence constraints), stations over the cycle time (see
+// CycleTime), and assignments failing any of the line's other constraints.
+//
+// Each assignment is checked against the other constraints as if it were
+// being made now, with the rest of its station already loaded. Violations
+// are listed with the unassigned tasks first, and then by station.
+func (l *Line) Violations() []Violation {
+	var violations []Violation
+	for _, task := range l.FreeTasks() {
+		violations = append(violations, Violation{
+			Kind:    UnassignedTask,
+			Task:    task.ID,
+			Station: Unassigned,
+			Message: fmt.Sprintf("task %d is not assigned to a station", task.ID),
+		})
+	}
+
+	cycle, limited := l.CycleTime()
+	for _, station := range l.Stations() {
+		if limited && station.Time() > cycle+eps {
+			violations = append(violations, Violation{
+				Kind:    StationOverload,
+				Station: station.ID,
+				Message: fmt.Sprintf("station %d time %.2f exceeds cycle time %.2f", station.ID, station.Time(), cycle),
+			})
+		}
+
+		for _, task := range append([]*Task(nil), station.Tasks()...) {
+			violations = append(violations, l.precedenceViolations(task, station)...)
+			violations = append(violations, l.constraintViolations(task, station)...)
+		}
+	}
+	return violations
+}
+
+// assignmentViolations returns the violations of the line's current balance
+// other than unassigned tasks, or nil if its assignments are all valid.
+func (l *Line) assignmentViolations() []Violation {
+	var violations []Violation
+	for _, v := range l.Violations() {
+		if v.Kind != UnassignedTask {
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+func (l *Line) precedenceViolations(task *Task, station *Station) []Violation {
+	var violations []Violation
+	for _, pred := range task.Preds() {
+		assignment := pred.Assignment()
+		if assignment != nil && assignment.ID <= station.ID {
+			continue
+		}
+
+		var message string
+		if assignment == nil {
+			message = fmt.Sprintf("task %d at station %d has unassigned predecessor %d",
+				task.ID, station.ID, pred.ID)
+		} else {
+			message = fmt.Sprintf("task %d at station %d precedes task %d at station %d",
+				pred.ID, assignment.ID, task.ID, station.ID)
+		}
+
+		violations = append(violations, Violation{
+			Kind:    PrecedenceViolation,
+			Task:    task.ID,
+			Station: station.ID,
+			Message: message,
+		})
+	}
+	return violations
+}
+
+// constraintViolations checks the task's assignment against the line's
+// constraints, skipping those whose violations are reported as precedence
+// violations or station overloads.
+func (l *Line) constraintViolations(task *Task, station *Station) []Violation {
+	i := station.index(task.ID)
+	err := station.WithdrawTask(task.ID)
+	if err != nil {
+		return nil
+	}
+	defer station.insertTask(i, task)
+
+	var violations []Violation
+	for _, constraint := range l.constraints {
+		switch constraint.(type) {
+		case *RestrictedStationTime, *PredecessorsStartToStart, *PredecessorsInPriorStations:
+			continue
+		}
+
+		if constraint.Valid(task, station) {
+			continue
+		}
+
+		violations = append(violations, Violation{
+			Kind:    ConstraintViolation,
+			Task:    task.ID,
+			Station: station.ID,
+			Message: fmt.Sprintf("task %d at station %d: %s", task.ID, station.ID, explain(constraint, task, station)),
+		})
+	}
+	return violations
+}
+
+// constraintName returns the name of a constraint's type.
+func constraintName(c Constraint) string {
+	t := reflect.TypeOf(c)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
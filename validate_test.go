@@ -0,0 +1,90 @@
+package alb
+
+import "testing"
+
+func TestViolations(t *testing.T) {
+	preds := [][2]int{{1, 2}, {3, 4}}
+	line := newTestLine("TestViolations", []float64{5, 4, 3, 3, 3}, preds, 10)
+	line.AddConstraint(&OnlyActiveStations{})
+
+	// Task 2 precedes its predecessor, station 2 is overloaded and inactive,
+	// task 4 has an unassigned predecessor and task 3 is free.
+	err := line.Apply(&Solution{Assignments: map[int]int{2: 1, 1: 2, 4: 2, 5: 2}})
+	if err != nil {
+		t.Fatalf("Apply returned an error, %s", err)
+	}
+	line.Station(2).Disable()
+
+	want := []Violation{
+		{Kind: UnassignedTask, Task: 3, Station: Unassigned},
+		{Kind: PrecedenceViolation, Task: 2, Station: 1},
+		{Kind: StationOverload, Task: 0, Station: 2},
+		{Kind: ConstraintViolation, Task: 1, Station: 2},
+		{Kind: PrecedenceViolation, Task: 4, Station: 2},
+		{Kind: ConstraintViolation, Task: 4, Station: 2},
+		{Kind: ConstraintViolation, Task: 5, Station: 2},
+	}
+
+	got := line.Violations()
+	if len(got) != len(want) {
+		t.Fatalf("line.Violations() = %d violations, got %d: %v", len(want), len(got), got)
+	}
+
+	for i, v := range want {
+		if got[i].Kind != v.Kind || got[i].Task != v.Task || got[i].Station != v.Station {
+			t.Errorf("line.Violations()[%d] = %s task %d station %d, got %s task %d station %d (%s)",
+				i, v.Kind, v.Task, v.Station, got[i].Kind, got[i].Task, got[i].Station, got[i])
+		}
+	}
+
+	verr, ok := line.Validate().(*ValidationError)
+	if !ok || len(verr.Violations) != len(want) {
+		t.Errorf("line.Validate() = *ValidationError with %d violations, got %v", len(want), line.Validate())
+	}
+}
+
+func TestValidateSolution(t *testing.T) {
+	line := newTestLine("TestValidateSolution", []float64{5, 4, 3}, [][2]int{{1, 2}}, 10)
+	err := line.BalanceByStationId(LongestTaskTime)
+	if err != nil {
+		t.Fatalf("BalanceByStationId returned an error, %s", err)
+	}
+
+	if err := line.Validate(); err != nil {
+		t.Errorf("line.Validate() = nil, got %s", err)
+	}
+
+	var tests = []struct {
+		assignments map[int]int
+		violations  int
+	}{
+		{map[int]int{1: 1, 2: 1, 3: 2}, 0},
+		{map[int]int{1: 2, 2: 1, 3: 2}, 1},
+		{map[int]int{1: 1, 2: 1, 3: 1}, 1},
+		{map[int]int{1: 1}, 2},
+	}
+
+	for _, test := range tests {
+		err := line.ValidateSolution(&Solution{Assignments: test.assignments})
+		if test.violations == 0 {
+			if err != nil {
+				t.Errorf("ValidateSolution(%v) = nil, got %s", test.assignments, err)
+			}
+			continue
+		}
+
+		verr, ok := err.(*ValidationError)
+		if !ok || len(verr.Violations) != test.violations {
+			t.Errorf("ValidateSolution(%v) = %d violations, got %v", test.assignments, test.violations, err)
+		}
+	}
+
+	if line.NFreeTasks() != 0 {
+		t.Errorf("ValidateSolution changed the line, got %d free tasks", line.NFreeTasks())
+	}
+
+	err = line.ValidateSolution(&Solution{Assignments: map[int]int{9: 1}})
+	if _, ok := err.(*ValidationError); err == nil || ok {
+		t.Errorf("ValidateSolution(unknown task) = error, got %v", err)
+	}
+}